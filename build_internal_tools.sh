@@ -14,6 +14,8 @@ LDFLAGS=(
 TOOLS=(
   validate-spec
   to-repo-data
+  spec-diff
+  spec-lint
 )
 
 for TOOL in "${TOOLS[@]}"
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	datasource_json "github.com/alserom/tg-bot-api-spec/pkg/datasource/json"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	spec_diff "github.com/alserom/tg-bot-api-spec/pkg/spec/diff"
+)
+
+var (
+	GoVersion  = runtime.Version()
+	CommitHash = "n/a"
+	BuildDate  = "n/a"
+	OsArch     = runtime.GOOS + "/" + runtime.GOARCH
+)
+
+const exitCodeFailOn = 2
+
+func main() {
+	format := flag.String("format", "markdown", "Output format: 'markdown', 'json' or 'both'")
+	output := flag.String(
+		"output",
+		"",
+		"Path to the output file without extension. '.md' and/or '.json' will be added according to the format. If empty - printing to stdout, which isn't supported by the 'both' format.",
+	)
+	failOn := flag.String(
+		"fail-on",
+		"none",
+		fmt.Sprintf("Exit with code %d if changes are detected: 'breaking', 'any' or 'none'", exitCodeFailOn),
+	)
+	help := flag.Bool("help", false, "Show help")
+
+	flag.Parse()
+
+	if *help {
+		showInfo()
+		return
+	}
+
+	if flag.NArg() != 2 {
+		showInfo()
+		os.Exit(1)
+	}
+
+	switch *failOn {
+	case "breaking", "any", "none":
+	default:
+		fmt.Printf("unknown value of 'fail-on' flag: %s\n", *failOn)
+		os.Exit(1)
+	}
+
+	cs, err := execute(flag.Arg(0), flag.Arg(1), *format, *output)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	if *failOn == "breaking" && cs.HasBreaking() || *failOn == "any" && !cs.IsEmpty() {
+		os.Exit(exitCodeFailOn)
+	}
+}
+
+func execute(oldPath, newPath, format, output string) (*spec_diff.ChangeSet, error) {
+	if format != "markdown" && format != "json" && format != "both" {
+		return nil, errors.New("unknown format: " + format)
+	}
+
+	// Both reports on stdout can't be told apart, so the JSON one couldn't be parsed.
+	if format == "both" && output == "" {
+		return nil, errors.New("format 'both' requires the output path")
+	}
+
+	oldSpec, err := loadSpec(oldPath)
+	if err != nil {
+		return nil, err
+	}
+
+	newSpec, err := loadSpec(newPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := spec_diff.Compare(oldSpec, newSpec)
+
+	content := make(map[string][]byte)
+	if format == "markdown" || format == "both" {
+		content[".md"] = []byte(cs.Markdown())
+	}
+	if format == "json" || format == "both" {
+		content[".json"], err = json.MarshalIndent(cs, "", "    ")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, ext := range []string{".md", ".json"} {
+		c, exists := content[ext]
+		if !exists {
+			continue
+		}
+
+		if output == "" {
+			fmt.Println(string(c))
+			continue
+		}
+
+		path, err := filepath.Abs(output + ext)
+		if err != nil {
+			return nil, err
+		}
+
+		err = os.WriteFile(path, c, 0644)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Println("saving: " + path)
+	}
+
+	return cs, nil
+}
+
+func loadSpec(filename string) (*spec.ApiSpec, error) {
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	datasource, err := datasource_json.NewDatasourceJson(path)
+	if err != nil {
+		return nil, errors.New(filename + ": " + err.Error())
+	}
+
+	as, err := spec.NewApiSpec(datasource)
+	if err != nil {
+		return nil, errors.New(filename + ": " + err.Error())
+	}
+
+	return as, nil
+}
+
+func showInfo() {
+	fmt.Println("Telegram Bot API spec diff")
+	fmt.Println("usage: spec-diff [flags] [path-to-old-spec] [path-to-new-spec]")
+	fmt.Println("example: spec-diff --format=both --output=report --fail-on=breaking old/spec.json new/spec.json")
+	fmt.Printf("- Go version: %s\n", GoVersion)
+	fmt.Printf("- Git commit: %s\n", CommitHash)
+	fmt.Printf("- Built:      %s\n", BuildDate)
+	fmt.Printf("- OS/Arch:    %s\n", OsArch)
+	flag.PrintDefaults()
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	datasource_json "github.com/alserom/tg-bot-api-spec/pkg/datasource/json"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	spec_lint "github.com/alserom/tg-bot-api-spec/pkg/spec/lint"
+)
+
+var (
+	GoVersion  = runtime.Version()
+	CommitHash = "n/a"
+	BuildDate  = "n/a"
+	OsArch     = runtime.GOOS + "/" + runtime.GOARCH
+)
+
+const exitCodeFailOn = 2
+
+func main() {
+	format := flag.String("format", "text", "Output format: 'text', 'json' or 'sarif'")
+	output := flag.String("output", "", "Path to the output file. If empty - printing to stdout.")
+	config := flag.String("config", "", "Path to the JSON config which disables rules or overrides their severities")
+	disable := flag.String("disable", "", "Comma-separated names of rules to disable in addition to the config")
+	failOn := flag.String(
+		"fail-on",
+		"error",
+		fmt.Sprintf("Exit with code %d if findings of the severity are detected: 'error', 'warning' or 'none'", exitCodeFailOn),
+	)
+	list := flag.Bool("list", false, "List available rules")
+	help := flag.Bool("help", false, "Show help")
+
+	flag.Parse()
+
+	if *help {
+		showInfo()
+		return
+	}
+
+	if *list {
+		for _, r := range spec_lint.DefaultRegistry().GetRules() {
+			fmt.Printf("%-22s %-8s %s\n", r.Name(), r.Severity(), r.Description())
+		}
+		return
+	}
+
+	if flag.NArg() != 1 {
+		showInfo()
+		os.Exit(1)
+	}
+
+	diagnostics, err := execute(flag.Arg(0), *format, *output, *config, *disable)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	switch *failOn {
+	case "error":
+		if diagnostics.HasErrors() {
+			os.Exit(exitCodeFailOn)
+		}
+	case "warning":
+		if diagnostics.HasErrors() || len(diagnostics.Warnings()) > 0 {
+			os.Exit(exitCodeFailOn)
+		}
+	case "none":
+	default:
+		fmt.Printf("unknown value of 'fail-on' flag: %s\n", *failOn)
+		os.Exit(1)
+	}
+}
+
+func execute(filename, format, output, configPath, disable string) (spec.Diagnostics, error) {
+	if format != "text" && format != "json" && format != "sarif" {
+		return nil, errors.New("unknown format: " + format)
+	}
+
+	var config spec_lint.Config
+	if configPath != "" {
+		var err error
+		config, err = spec_lint.LoadConfig(configPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range strings.Split(disable, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			config.Disabled = append(config.Disabled, name)
+		}
+	}
+
+	linter, err := spec_lint.NewLinter(spec_lint.DefaultRegistry(), config)
+	if err != nil {
+		return nil, err
+	}
+
+	as, err := loadSpec(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostics := linter.Lint(as)
+
+	var content []byte
+	switch format {
+	case "text":
+		content = []byte(text(diagnostics))
+	case "json":
+		content, err = json.MarshalIndent(diagnostics, "", "    ")
+	case "sarif":
+		content, err = spec_lint.SARIF(diagnostics, linter.GetRules(), CommitHash)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if output == "" {
+		fmt.Println(string(content))
+
+		return diagnostics, nil
+	}
+
+	path, err := filepath.Abs(output)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.WriteFile(path, content, 0644)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("saving: " + path)
+
+	return diagnostics, nil
+}
+
+func text(diagnostics spec.Diagnostics) string {
+	if len(diagnostics) == 0 {
+		return "No problems detected."
+	}
+
+	var sb strings.Builder
+	for _, d := range diagnostics {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", d.Severity, d.Error()))
+	}
+	sb.WriteString(fmt.Sprintf(
+		"\n%d errors, %d warnings, %d infos",
+		len(diagnostics.Errors()),
+		len(diagnostics.Warnings()),
+		len(diagnostics.Filter(spec.SeverityInfo)),
+	))
+
+	return sb.String()
+}
+
+func loadSpec(filename string) (*spec.ApiSpec, error) {
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	datasource, err := datasource_json.NewDatasourceJson(path)
+	if err != nil {
+		return nil, errors.New(filename + ": " + err.Error())
+	}
+
+	as, err := spec.NewApiSpec(datasource)
+	if err != nil {
+		return nil, errors.New(filename + ": " + err.Error())
+	}
+
+	return as, nil
+}
+
+func showInfo() {
+	fmt.Println("Telegram Bot API spec linter")
+	fmt.Println("usage: spec-lint [flags] [path-to-spec]")
+	fmt.Println("example: spec-lint --format=sarif --output=lint.sarif --disable=unreferenced-type spec.json")
+	fmt.Printf("- Go version: %s\n", GoVersion)
+	fmt.Printf("- Git commit: %s\n", CommitHash)
+	fmt.Printf("- Built:      %s\n", BuildDate)
+	fmt.Printf("- OS/Arch:    %s\n", OsArch)
+	flag.PrintDefaults()
+}
@@ -1,16 +1,23 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/alserom/tg-bot-api-spec/internal/datasource/scrape"
 	export_to_openapi "github.com/alserom/tg-bot-api-spec/internal/export/openapi"
+	"github.com/alserom/tg-bot-api-spec/pkg/changelog"
+	datasource_composite "github.com/alserom/tg-bot-api-spec/pkg/datasource/composite"
+	datasource_json "github.com/alserom/tg-bot-api-spec/pkg/datasource/json"
+	datasource_overlay "github.com/alserom/tg-bot-api-spec/pkg/datasource/overlay"
 	export_to_json "github.com/alserom/tg-bot-api-spec/pkg/export/json"
 	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	spec_history "github.com/alserom/tg-bot-api-spec/pkg/spec/history"
 )
 
 var (
@@ -20,6 +27,32 @@ var (
 	OsArch     = runtime.GOOS + "/" + runtime.GOARCH
 )
 
+// fileList collects values of the flag which can be passed several times.
+type fileList []string
+
+func (fl *fileList) String() string {
+	return strings.Join(*fl, ",")
+}
+
+func (fl *fileList) Set(value string) error {
+	*fl = append(*fl, value)
+	return nil
+}
+
+type sourceOptions struct {
+	source      string
+	supplements []string
+	overlays    []string
+	onConflict  string
+	lenient     bool
+	fetch       scrape.FetchOptions
+	// provenance enables exporting of provenance.json with places of the page which elements were scraped from.
+	provenance bool
+	// changelog enables exporting of changelog.json, changelogSource is the path to the changelog page.
+	changelog       bool
+	changelogSource string
+}
+
 type Exporter interface {
 	Export(filename string) error
 }
@@ -31,6 +64,36 @@ func main() {
 		"Path to '*.html' file which can be a data source for scraping. If empty - scraping https://core.telegram.org/bots/api.",
 	)
 	dir := flag.String("dir", "", "Path to the output directory")
+	order := flag.String(
+		"order",
+		"alphabetical",
+		"Order of properties and arguments in the exported files: 'alphabetical' or 'document' (as described in the official doc)",
+	)
+	history := flag.String(
+		"history",
+		"",
+		"Glob pattern of JSON spec files of previous Bot API versions, e.g. 'history/*/api.json'. If set, elements are annotated with versions which introduced, changed and removed them.",
+	)
+	includeMethods := flag.String("include-methods", "", "Comma-separated names of methods to export with the types they refer to. If empty - exporting the whole spec.")
+	includeCategories := flag.String("include-categories", "", "Comma-separated categories of methods and types to export with the types they refer to")
+	var supplements, overlays fileList
+	flag.Var(&supplements, "supplement", "Path to the JSON spec file which supplements the scraped spec. Can be passed several times.")
+	onConflict := flag.String(
+		"on-conflict",
+		"error",
+		"What to do when supplements fill the same element: 'first-wins', 'last-wins' or 'error'",
+	)
+	flag.Var(&overlays, "overlay", "Path to the JSON overlay with corrections of the scraped spec. Can be passed several times, overlays are applied in order.")
+	exportChangelog := flag.Bool("changelog", false, "Export the official Bot API changelog to changelog.json")
+	changelogSource := flag.String(
+		"changelog-source",
+		"",
+		"Path to '*.html' file of the changelog page. If empty - scraping https://core.telegram.org/bots/api-changelog.",
+	)
+	lenient := flag.Bool("lenient", false, "Don't stop on the first scraping problem, report all of them. Nothing is exported if there are errors.")
+	withProvenance := flag.Bool("with-provenance", false, "Export provenance.json with places of the scraped page which elements come from")
+	cacheDir := flag.String("cache-dir", "", "Directory to cache fetched pages. Cached pages are downloaded again only if they're changed.")
+	baseUrl := flag.String("base-url", "", "Base URL to fetch pages from instead of https://core.telegram.org, e.g. a local mirror")
 	help := flag.Bool("help", false, "Show help")
 
 	flag.Parse()
@@ -40,14 +103,40 @@ func main() {
 		return
 	}
 
-	err := execute(*source, *dir)
+	selection := spec.Selection{
+		Methods:    splitList(*includeMethods),
+		Categories: splitList(*includeCategories),
+	}
+
+	fetch := scrape.DefaultFetchOptions()
+	fetch.CacheDir = *cacheDir
+	fetch.BaseUrl = *baseUrl
+
+	sources := sourceOptions{
+		source:          *source,
+		supplements:     supplements,
+		overlays:        overlays,
+		onConflict:      *onConflict,
+		lenient:         *lenient,
+		fetch:           fetch,
+		provenance:      *withProvenance,
+		changelog:       *exportChangelog || *changelogSource != "",
+		changelogSource: *changelogSource,
+	}
+
+	err := execute(sources, *dir, *order, *history, selection)
 	if err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
 	}
 }
 
-func execute(source, dir string) error {
+func execute(sources sourceOptions, dir, order, history string, selection spec.Selection) error {
+	ordering, err := getOrdering(order)
+	if err != nil {
+		return err
+	}
+
 	fail := true
 	out, isCreated, err := prepareDir(dir)
 	if err != nil {
@@ -58,27 +147,79 @@ func execute(source, dir string) error {
 	}
 
 	fmt.Println("initializing data source...")
-	datasource, err := getDatasource(source)
+	scraper, err := getScraper(sources.source, sources.fetch)
+	if err != nil {
+		return err
+	}
+	scraper.SetLenient(sources.lenient)
+
+	datasource, err := getDatasource(scraper, sources)
 	if err != nil {
 		return err
 	}
 
 	fmt.Println("creating specification...")
 	spec, err := spec.NewApiSpec(datasource)
+	problems := scraper.GetProblems()
+	if sources.lenient {
+		printProblems(problems)
+		if err == nil && problems.HasErrors() {
+			err = errors.New(fmt.Sprintf("scraping failed with %d errors", len(problems.Errors())))
+		}
+	} else {
+		printProblems(problems.Warnings())
+	}
 	if err != nil {
 		return err
 	}
 
 	fmt.Printf("Bot API v%s created\n", spec.GetVersion())
 
+	for _, warning := range spec.Diagnose().Warnings() {
+		fmt.Println("warning: " + warning.Error())
+	}
+
+	if history != "" {
+		err = annotateHistory(spec, history)
+		if err != nil {
+			return err
+		}
+	}
+
+	var exporters []Exporter
+	if sources.changelog {
+		changelogExporter, err := getChangelogExporter(sources.changelogSource, sources.fetch, spec)
+		if err != nil {
+			return err
+		}
+		exporters = append(exporters, changelogExporter)
+	}
+
+	if !selection.IsEmpty() {
+		fmt.Println("selecting subset...")
+		spec, err = spec.Subset(selection)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("subset of %d types and %d methods selected\n", len(spec.GetTypes()), len(spec.GetMethods()))
+	}
+
 	fmt.Println("creating exporters...")
 
-	jsonExporter, err := export_to_json.NewApiSpecExporter(*spec)
+	if sources.provenance {
+		provenanceExporter, err := export_to_json.NewProvenanceExporter(*spec)
+		if err != nil {
+			return err
+		}
+		exporters = append(exporters, provenanceExporter)
+	}
+
+	jsonExporter, err := export_to_json.NewOrderedApiSpecExporter(*spec, ordering)
 	if err != nil {
 		return err
 	}
 
-	openapiExporter, err := export_to_openapi.NewOpenapiExporter(*spec)
+	openapiExporter, err := export_to_openapi.NewOrderedOpenapiExporter(*spec, ordering)
 	if err != nil {
 		return err
 	}
@@ -95,6 +236,13 @@ func execute(source, dir string) error {
 		return err
 	}
 
+	for _, exporter := range exporters {
+		err = exporter.Export(out)
+		if err != nil {
+			return err
+		}
+	}
+
 	outVersion := out + "/version.json"
 	err = os.WriteFile(outVersion, []byte(fmt.Sprintf(`{"version":"%s"}`, spec.GetVersion())), 0644)
 	fmt.Println("saving: " + outVersion)
@@ -107,9 +255,116 @@ func execute(source, dir string) error {
 	return nil
 }
 
-func getDatasource(source string) (spec.DataSource, error) {
+// getDatasource combines the scraper with supplements, overlays are applied after all of them.
+func getDatasource(scraper *scrape.Scraper, sources sourceOptions) (spec.DataSource, error) {
+	if len(sources.supplements)+len(sources.overlays) == 0 {
+		return scraper, nil
+	}
+
+	strategy, err := datasource_composite.ParseStrategy(sources.onConflict)
+	if err != nil {
+		return nil, err
+	}
+
+	composite, err := datasource_composite.NewDatasourceComposite(strategy)
+	if err != nil {
+		return nil, err
+	}
+	if err := composite.AddSource("scraper", scraper); err != nil {
+		return nil, err
+	}
+
+	for _, s := range sources.supplements {
+		path, err := filepath.Abs(s)
+		if err != nil {
+			return nil, err
+		}
+
+		supplement, err := datasource_json.NewDatasourceJson(path)
+		if err != nil {
+			return nil, errors.New(s + ": " + err.Error())
+		}
+		if err := composite.AddSource(s, supplement); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, o := range sources.overlays {
+		path, err := filepath.Abs(o)
+		if err != nil {
+			return nil, err
+		}
+
+		overlay, err := datasource_overlay.LoadOverlay(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := composite.AddPatcher(o, overlay); err != nil {
+			return nil, err
+		}
+	}
+
+	return composite, nil
+}
+
+func annotateHistory(as *spec.ApiSpec, pattern string) error {
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return errors.New("no history files match " + pattern)
+	}
+
+	fmt.Printf("annotating with history of %d versions...\n", len(paths))
+	history, err := spec_history.Load(paths...)
+	if err != nil {
+		return err
+	}
+
+	return history.Annotate(as)
+}
+
+// getChangelogExporter resolves references of the changelog against the whole spec, so the subset doesn't produce false warnings.
+func getChangelogExporter(source string, fetch scrape.FetchOptions, as *spec.ApiSpec) (Exporter, error) {
+	fmt.Println("creating changelog...")
+	scraper, err := getChangelogScraper(source, fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	if changelogScraper, ok := scraper.(*scrape.ChangelogScraper); ok {
+		printProblems(changelogScraper.GetProblems())
+	}
+
+	cl, err := changelog.NewChangelog(scraper)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range cl.ResolveReferences(as) {
+		fmt.Printf("warning: changelog refers to unknown anchor #%s (%s)\n", r.GetAnchor(), r.GetText())
+	}
+
+	return export_to_json.NewChangelogExporter(cl)
+}
+
+func getChangelogScraper(source string, fetch scrape.FetchOptions) (changelog.DataSource, error) {
+	if source == "" {
+		return scrape.NewChangelogScraperWithOptions(fetch)
+	}
+
+	path, err := filepath.Abs(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return scrape.NewFileChangelogScraper(path)
+}
+
+func getScraper(source string, fetch scrape.FetchOptions) (*scrape.Scraper, error) {
 	if source == "" {
-		return scrape.NewScraper()
+		return scrape.NewScraperWithOptions(fetch)
 	}
 
 	path, err := filepath.Abs(source)
@@ -120,6 +375,37 @@ func getDatasource(source string) (spec.DataSource, error) {
 	return scrape.NewFileScraper(path)
 }
 
+func printProblems(problems scrape.Problems) {
+	for _, p := range problems {
+		fmt.Printf("%s: [%s] %s: %s\n", p.Severity, p.Code, p.Location(), p.Message)
+		if p.Snippet != "" && p.Severity != spec.SeverityInfo {
+			fmt.Println("    " + strings.ReplaceAll(p.Snippet, "\n", "\n    "))
+		}
+	}
+}
+
+func getOrdering(order string) (spec.Ordering, error) {
+	switch order {
+	case "alphabetical":
+		return spec.OrderAlphabetical, nil
+	case "document":
+		return spec.OrderDocument, nil
+	}
+
+	return spec.OrderAlphabetical, errors.New("unknown order: " + order)
+}
+
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
 func prepareDir(dir string) (string, bool, error) {
 	outPath, err := filepath.Abs(dir)
 	if err != nil {
@@ -0,0 +1,41 @@
+package scrape
+
+import (
+	"strings"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+// knownAliases lists the recurring unions which are named in the spec. Chat identifiers are documented as `Integer or String`,
+// so they're scraped as `int32|string`.
+var knownAliases = []struct {
+	name        string
+	definitions []string
+}{
+	{"ChatId", []string{"int32", "string"}},
+	{"FileInput", []string{"InputFile", "string"}},
+}
+
+// declareAliases names the known unions which are used in the spec.
+func declareAliases(as *spec.ApiSpec) error {
+	for _, known := range knownAliases {
+		if _, exists := as.GetAlias(known.name); exists {
+			continue
+		}
+
+		expr, err := spec.ParseTypeExpression(strings.Join(known.definitions, "|"))
+		if err != nil {
+			return err
+		}
+
+		if _, used := as.GetDataTypeDefinitions()[expr.String()]; !used {
+			continue
+		}
+
+		if _, err := as.DeclareAlias(known.name, known.definitions...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
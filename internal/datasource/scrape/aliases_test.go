@@ -0,0 +1,98 @@
+package scrape
+
+import (
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+func TestDeclareAliases(t *testing.T) {
+	as := spectest.New(t, func(as *spec.ApiSpec) error {
+		spectest.AddType(as, "InputFile")
+		spectest.AddType(as, "ChatJoinRequest", "user_chat_id int64", "sender_chat_id int32|string")
+		spectest.AddMethod(as, "forwardMessage", "", "chat_id int32|string", "from_chat_id int64|string", "message_id int32")
+		spectest.AddMethod(as, "sendDocument", "", "chat_id int32|string", "document InputFile|string")
+
+		return declareAliases(as)
+	})
+
+	forwardMessage, _ := as.GetMethod("forwardMessage")
+	sendDocument, _ := as.GetMethod("sendDocument")
+	chatJoinRequest, _ := as.GetType("ChatJoinRequest")
+
+	tests := []struct {
+		name     string
+		dataType spec.DataTypeDefinition
+		want     string
+		alias    string
+	}{
+		{"chat id", argumentType(t, forwardMessage, "chat_id"), "int32|string", "ChatId"},
+		{"chat id of the type", propertyType(t, chatJoinRequest, "sender_chat_id"), "int32|string", "ChatId"},
+		{"chat id of another method", argumentType(t, sendDocument, "chat_id"), "int32|string", "ChatId"},
+		{"unknown union", argumentType(t, forwardMessage, "from_chat_id"), "int64|string", ""},
+		{"file", argumentType(t, sendDocument, "document"), "InputFile|string", "FileInput"},
+	}
+
+	chatId, exists := as.GetAlias("ChatId")
+	if !exists {
+		t.Fatal("alias ChatId isn't declared")
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.dataType.GetDefinition() != tt.want {
+				t.Fatalf("got %s, want %s", tt.dataType.GetDefinition(), tt.want)
+			}
+
+			var alias string
+			if union, ok := tt.dataType.(*spec.UnionDataType); ok && union.GetAlias() != nil {
+				alias = union.GetAlias().GetName()
+			}
+			if alias != tt.alias {
+				t.Errorf("got alias '%s', want '%s'", alias, tt.alias)
+			}
+			if tt.alias == "ChatId" && tt.dataType != spec.DataTypeDefinition(chatId.GetDataType()) {
+				t.Errorf("data type isn't shared with the alias")
+			}
+		})
+	}
+}
+
+func TestDeclareAliasesOfUnusedUnions(t *testing.T) {
+	as := spectest.New(t, func(as *spec.ApiSpec) error {
+		spectest.AddMethod(as, "getMe", "", "user_id int64")
+
+		return declareAliases(as)
+	})
+
+	if len(as.GetAliases()) != 0 {
+		t.Errorf("expected no aliases, got %v", as.GetAliases())
+	}
+}
+
+func argumentType(t *testing.T, m *spec.TgMethodSpec, name string) spec.DataTypeDefinition {
+	t.Helper()
+
+	for _, a := range m.GetArguments() {
+		if a.GetName() == name {
+			return a.GetDataTypes()[0]
+		}
+	}
+	t.Fatalf("argument %s doesn't exist", name)
+
+	return nil
+}
+
+func propertyType(t *testing.T, tgType *spec.TgTypeSpec, name string) spec.DataTypeDefinition {
+	t.Helper()
+
+	for _, p := range tgType.GetProperties() {
+		if p.GetName() == name {
+			return p.GetDataTypes()[0]
+		}
+	}
+	t.Fatalf("property %s doesn't exist", name)
+
+	return nil
+}
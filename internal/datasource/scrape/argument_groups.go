@@ -0,0 +1,114 @@
+package scrape
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/alserom/tg-bot-api-spec/internal/utils"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+// argumentRelations collects relations between arguments of a method found in their descriptions.
+type argumentRelations struct {
+	requiredIfAbsent  map[string][]string
+	mutuallyExclusive map[string][]string
+}
+
+func newArgumentRelations() *argumentRelations {
+	return &argumentRelations{
+		requiredIfAbsent:  make(map[string][]string),
+		mutuallyExclusive: make(map[string][]string),
+	}
+}
+
+func (ar *argumentRelations) collect(argument, description string) {
+	if names := extractRequiredIfAbsent(description); len(names) > 0 {
+		ar.requiredIfAbsent[argument] = names
+	}
+
+	if names := extractMutuallyExclusive(description); len(names) > 0 {
+		ar.mutuallyExclusive[argument] = names
+	}
+}
+
+// addArgumentGroups converts collected relations to the argument groups of the method.
+// Arguments which depend on the same arguments are joined into one RequiredIfAbsent group.
+// Two arguments which are required if the other one is not specified form an ExactlyOneOf group.
+// Groups of the same kind with the same arguments and dependencies are added once. Groups with the swapped sides,
+// e.g. `chat_id, message_id` required if `inline_message_id` is absent and vice versa, are different requirements.
+func (ar *argumentRelations) addArgumentGroups(m *spec.TgMethodSpec) {
+	known := make(map[string]bool)
+	for _, a := range m.GetArguments() {
+		known[a.GetName()] = true
+	}
+
+	var groups []*spec.TgMethodSpecArgumentGroup
+	seen := make(map[string]bool)
+	addGroup := func(kind spec.ArgumentGroupKind, arguments, dependsOn []string) {
+		for _, name := range append(append([]string{}, arguments...), dependsOn...) {
+			if !known[name] {
+				return
+			}
+		}
+
+		key := string(kind) + ":" + sortedJoin(arguments) + ";" + sortedJoin(dependsOn)
+
+		group, err := spec.NewTgMethodSpecArgumentGroup(kind, arguments, dependsOn)
+		if err == nil && !seen[key] {
+			seen[key] = true
+			groups = append(groups, group)
+		}
+	}
+
+	byDependencies := make(map[string][]string)
+	for _, arg := range sortedKeys(ar.requiredIfAbsent) {
+		dependsOn := ar.requiredIfAbsent[arg]
+		key := strings.Join(dependsOn, ",")
+		byDependencies[key] = append(byDependencies[key], arg)
+
+		for _, other := range dependsOn {
+			if utils.Contains(ar.requiredIfAbsent[other], arg) {
+				addGroup(spec.ExactlyOneOf, sortedPair(arg, other), nil)
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(byDependencies) {
+		addGroup(spec.RequiredIfAbsent, byDependencies[key], strings.Split(key, ","))
+	}
+
+	for _, arg := range sortedKeys(ar.mutuallyExclusive) {
+		for _, other := range ar.mutuallyExclusive[arg] {
+			addGroup(spec.MutuallyExclusive, sortedPair(arg, other), nil)
+		}
+	}
+
+	for _, group := range groups {
+		m.AddArgumentGroup(group)
+	}
+}
+
+func sortedKeys(m map[string][]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedJoin(names []string) string {
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+
+	return strings.Join(sorted, ",")
+}
+
+func sortedPair(a, b string) []string {
+	if b < a {
+		return []string{b, a}
+	}
+
+	return []string{a, b}
+}
@@ -0,0 +1,36 @@
+package scrape
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+func TestAddArgumentGroupsDeduplicates(t *testing.T) {
+	m, _ := spec.NewTgMethodSpec("updating-messages", "editMessageText", "https://core.telegram.org/bots/api#editmessagetext")
+	descriptions := map[string]string{
+		"chat_id":           "Required if inline_message_id is not specified. Unique identifier for the target chat",
+		"message_id":        "Required if inline_message_id is not specified. Identifier of the message to edit",
+		"inline_message_id": "Required if chat_id and message_id are not specified. Identifier of the inline message",
+	}
+
+	relations := newArgumentRelations()
+	for _, name := range []string{"chat_id", "message_id", "inline_message_id"} {
+		a, _ := spec.NewTgMethodSpecArgument(name)
+		m.AddArgument(a)
+		relations.collect(name, descriptions[name])
+	}
+	relations.addArgumentGroups(m)
+
+	var groups []string
+	for _, g := range m.GetArgumentGroups() {
+		groups = append(groups, g.String())
+	}
+
+	want := []string{"exactlyOneOf(chat_id, inline_message_id)", "exactlyOneOf(inline_message_id, message_id)",
+		"requiredIfAbsent(inline_message_id; chat_id, message_id)", "requiredIfAbsent(chat_id, message_id; inline_message_id)"}
+	if strings.Join(groups, " ") != strings.Join(want, " ") {
+		t.Errorf("got groups %v, want %v", groups, want)
+	}
+}
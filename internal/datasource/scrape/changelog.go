@@ -0,0 +1,142 @@
+package scrape
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/changelog"
+)
+
+var releaseVersionRegexp = regexp.MustCompile(`Bot API (\d+(?:\.\d+)*)`)
+
+// ChangelogScraper fills the changelog from the page of the official Bot API changelog.
+type ChangelogScraper struct {
+	doc      *goquery.Document
+	problems Problems
+}
+
+func NewChangelogScraper() (*ChangelogScraper, error) {
+	return NewChangelogScraperWithOptions(DefaultFetchOptions())
+}
+
+func NewChangelogScraperWithOptions(options FetchOptions) (*ChangelogScraper, error) {
+	f := newFetcher(options)
+	doc, err := f.fetch(url_changelog)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChangelogScraper{doc: doc, problems: f.problems}, nil
+}
+
+func NewFileChangelogScraper(path string) (*ChangelogScraper, error) {
+	doc, err := openDocument(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChangelogScraper{doc: doc}, nil
+}
+
+// GetProblems returns warnings of fetching the page.
+func (cs ChangelogScraper) GetProblems() Problems {
+	return cs.problems
+}
+
+// FillChangelog treats every h4 as a release: the first paragraph with `Bot API x.y` gives the version,
+// bullets and other paragraphs are entries.
+func (cs *ChangelogScraper) FillChangelog(cl *changelog.Changelog) error {
+	if cs.doc == nil {
+		return errors.New("document missed, nothing to scrape")
+	}
+
+	var release *changelog.Release
+	var err error
+	cs.doc.Find("#dev_page_content").Children().EachWithBreak(func(i int, s *goquery.Selection) bool {
+		nodeName := goquery.NodeName(s)
+		switch nodeName {
+		case "h3":
+			release = nil
+		case "h4":
+			anchor := s.Find("a.anchor")
+			if _, exists := anchor.Attr("name"); !exists {
+				err = errors.New(fmt.Sprintf("scraping error: detected node %s without anchor", nodeName))
+				return false
+			}
+
+			release, err = changelog.NewRelease(strings.TrimSpace(s.Text()), hrefToLink(anchor.AttrOr("href", ""), url_changelog))
+			if err != nil {
+				err = errors.New("scraping error: can't create new release. error: " + err.Error())
+				return false
+			}
+			cl.AddRelease(release)
+		case "p", "blockquote":
+			if release == nil {
+				return true
+			}
+
+			if matches := releaseVersionRegexp.FindStringSubmatch(s.Text()); release.GetVersion() == "" && len(matches) == 2 {
+				release.SetVersion(matches[1])
+				return true
+			}
+
+			if entry := newChangelogEntry(s); entry != nil {
+				release.AddEntry(entry)
+			}
+		case "ul", "ol":
+			if release == nil {
+				return true
+			}
+
+			for _, entry := range newChangelogEntries(s) {
+				release.AddEntry(entry)
+			}
+		}
+
+		return true
+	})
+
+	return err
+}
+
+func newChangelogEntries(list *goquery.Selection) []*changelog.Entry {
+	var entries []*changelog.Entry
+	list.ChildrenFiltered("li").Each(func(i int, li *goquery.Selection) {
+		entry := newChangelogEntry(li)
+		if entry == nil {
+			return
+		}
+
+		li.ChildrenFiltered("ul, ol").Each(func(i int, nested *goquery.Selection) {
+			for _, e := range newChangelogEntries(nested) {
+				entry.AddEntry(e)
+			}
+		})
+		entries = append(entries, entry)
+	})
+
+	return entries
+}
+
+// newChangelogEntry returns nil for empty nodes. Nested lists aren't part of the entry text.
+func newChangelogEntry(s *goquery.Selection) *changelog.Entry {
+	node := s.Clone()
+	node.Find("ul, ol").Remove()
+
+	entry, err := changelog.NewEntry(strings.Join(strings.Fields(node.Text()), " "))
+	if err != nil {
+		return nil
+	}
+
+	node.Find("a[href]").Each(func(i int, a *goquery.Selection) {
+		if reference, err := changelog.NewReference(a.Text(), hrefToLink(a.AttrOr("href", ""), url_changelog)); err == nil {
+			entry.AddReference(reference)
+		}
+	})
+
+	return entry
+}
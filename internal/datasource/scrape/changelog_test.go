@@ -0,0 +1,57 @@
+package scrape
+
+import (
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/changelog"
+)
+
+func TestFillChangelog(t *testing.T) {
+	cs, err := NewFileChangelogScraper("testdata/changelog.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cl, err := changelog.NewChangelog(cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	releases := cl.GetReleases()
+	if len(releases) != 2 {
+		t.Fatalf("got %d releases, want 2", len(releases))
+	}
+
+	release := releases[0]
+	if release.GetVersion() != "7.0" || release.GetDate() != "December 29, 2023" || release.GetLink() != "https://core.telegram.org/bots/api-changelog#december-29-2023" {
+		t.Errorf("got release %s of %s at %s", release.GetVersion(), release.GetDate(), release.GetLink())
+	}
+
+	entries := release.GetEntries()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].GetText() != "Reactions" || len(entries[0].GetReferences()) != 0 {
+		t.Errorf("nested entries are part of the entry: %s %v", entries[0].GetText(), entries[0].GetReferences())
+	}
+
+	children := entries[0].GetEntries()
+	if len(children) != 2 || children[0].GetText() != "Added the class ReactionType." || children[1].GetText() != "Added the method setMessageReaction." {
+		t.Fatalf("unexpected child entries %v", children)
+	}
+
+	references := entries[1].GetReferences()
+	if entries[1].GetText() != "Added the parameter reply_parameters to the method sendMessage." {
+		t.Errorf("got entry %s", entries[1].GetText())
+	}
+	if len(references) != 1 || references[0].GetText() != "sendMessage" || references[0].GetLink() != "https://core.telegram.org/bots/api#sendmessage" {
+		t.Errorf("unexpected references %v", references)
+	}
+
+	release = releases[1]
+	if release.GetVersion() != "" || release.GetDate() != "June 24, 2015" {
+		t.Errorf("got release %s of %s, want no version", release.GetVersion(), release.GetDate())
+	}
+	if len(release.GetEntries()) != 1 || release.GetEntries()[0].GetText() != "The bot platform was officially launched." {
+		t.Errorf("unexpected entries %v", release.GetEntries())
+	}
+}
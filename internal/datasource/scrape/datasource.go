@@ -3,9 +3,8 @@ package scrape
 import (
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
+	"sort"
 
 	"github.com/PuerkitoBio/goquery"
 
@@ -16,7 +15,11 @@ const url string = "https://core.telegram.org"
 const url_api_doc string = url + "/bots/api"
 
 type Scraper struct {
-	doc *goquery.Document
+	doc      *goquery.Document
+	lenient  bool
+	problems Problems
+	// fetchProblems are warnings of fetching the page, they're reported by every scraping.
+	fetchProblems Problems
 }
 
 type tgVersionSpec struct {
@@ -32,47 +35,71 @@ type deferredTgTypeSpecChild struct {
 
 type helper struct {
 	declareDataType func(definition string) spec.DataTypeDefinition
+	lenient         bool
 }
 
-func createHelper(as *spec.ApiSpec) helper {
+func createHelper(as *spec.ApiSpec, lenient bool) helper {
 	return helper{
 		declareDataType: func(definition string) spec.DataTypeDefinition {
 			return as.DeclareDataType(definition)
 		},
+		lenient: lenient,
 	}
 }
 
-func createScraper(r io.Reader) (*Scraper, error) {
-	doc, err := goquery.NewDocumentFromReader(r)
-	if err != nil {
-		return nil, err
-	}
+// report sends the problem and tells whether scraping goes on.
+func (h helper) report(ch chan interface{}, p *Problem) bool {
+	ch <- p
 
-	return &Scraper{doc: doc}, nil
+	return h.lenient || p.Severity != spec.SeverityError
 }
 
 func NewScraper() (*Scraper, error) {
-	res, err := http.Get(url_api_doc)
+	return NewScraperWithOptions(DefaultFetchOptions())
+}
+
+func NewScraperWithOptions(options FetchOptions) (*Scraper, error) {
+	f := newFetcher(options)
+	doc, err := f.fetch(url_api_doc)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	if res.StatusCode != 200 {
-		return nil, errors.New(fmt.Sprintf("Get \"%s\": %d %s", url_api_doc, res.StatusCode, res.Status))
+	return &Scraper{doc: doc, fetchProblems: f.problems}, nil
+}
+
+func NewFileScraper(path string) (*Scraper, error) {
+	doc, err := openDocument(path)
+	if err != nil {
+		return nil, err
 	}
 
-	return createScraper(res.Body)
+	return &Scraper{doc: doc}, nil
 }
 
-func NewFileScraper(path string) (*Scraper, error) {
+func openDocument(path string) (*goquery.Document, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
 
-	return createScraper(f)
+	return goquery.NewDocumentFromReader(f)
+}
+
+// SetLenient makes the scraper go on after problems, so the spec is filled partially and all the problems are reported.
+func (s *Scraper) SetLenient(lenient bool) {
+	s.lenient = lenient
+}
+
+func (s Scraper) IsLenient() bool {
+	return s.lenient
+}
+
+// GetProblems returns problems of the last scraping, warnings of fetching the page go first.
+// Without lenient mode, the error problem is the last one.
+func (s Scraper) GetProblems() Problems {
+	return s.problems
 }
 
 func (s *Scraper) FillApiSpec(as *spec.ApiSpec) error {
@@ -80,9 +107,19 @@ func (s *Scraper) FillApiSpec(as *spec.ApiSpec) error {
 		return errors.New("document missed, nothing to scrape")
 	}
 
-	childToParent := make(map[string]*spec.TgTypeSpec)
+	s.problems = append(Problems(nil), s.fetchProblems...)
+	childToParents := make(map[string][]*spec.TgTypeSpec)
+
+	items := scrape(s.doc, createHelper(as, s.lenient))
+	// The scraper may still send the last item after the error, so the rest is drained.
+	defer func() {
+		go func() {
+			for range items {
+			}
+		}()
+	}()
 
-	for scrapeItem := range scrape(s.doc, createHelper(as)) {
+	for scrapeItem := range items {
 		switch item := scrapeItem.(type) {
 		case *tgVersionSpec:
 			if as.GetVersion() == "" {
@@ -92,39 +129,66 @@ func (s *Scraper) FillApiSpec(as *spec.ApiSpec) error {
 			}
 		case *spec.TgTypeSpec:
 			key := item.GetName()
-			if parent, exists := childToParent[key]; exists {
-				item.SetParent(parent)
+			for _, parent := range childToParents[key] {
+				item.AddParent(parent)
 				parent.AddChild(item)
-				delete(childToParent, key)
 			}
+			delete(childToParents, key)
 
 			as.AddType(item)
 		case *spec.TgMethodSpec:
 			as.AddMethod(item)
 		case *deferredTgTypeSpecChild:
-			childToParent[item.childName] = item.parent
+			childToParents[item.childName] = append(childToParents[item.childName], item.parent)
+		case *Problem:
+			s.problems = append(s.problems, *item)
+			if !s.lenient && item.Severity == spec.SeverityError {
+				return item
+			}
 		case error:
 			return item
 		}
 	}
 
-	if len(childToParent) > 0 {
-		for childName, parent := range childToParent {
-			if child, ok := as.GetType(childName); ok {
-				child.SetParent(parent)
+	for childName, parents := range childToParents {
+		if child, ok := as.GetType(childName); ok {
+			for _, parent := range parents {
+				child.AddParent(parent)
 				parent.AddChild(child)
-				delete(childToParent, childName)
 			}
+			delete(childToParents, childName)
 		}
 	}
 
-	if len(childToParent) > 0 {
+	if len(childToParents) > 0 {
 		msg := "some types were not added to the spec:"
-		for childName := range childToParent {
+		for childName := range childToParents {
 			msg += "\n- " + childName
 		}
-		return errors.New(msg)
+		if !s.lenient {
+			return errors.New(msg)
+		}
+
+		childNames := make([]string, 0, len(childToParents))
+		for childName := range childToParents {
+			childNames = append(childNames, childName)
+		}
+		sort.Strings(childNames)
+
+		for _, childName := range childNames {
+			for _, parent := range childToParents[childName] {
+				problem := Problem{
+					Code:     CodeUnknownChild,
+					Severity: spec.SeverityError,
+					Message:  fmt.Sprintf("type '%s' listed as a child of '%s' isn't found", childName, parent.GetName()),
+				}
+				if provenance := parent.GetProvenance(); provenance != nil {
+					problem.Anchor, problem.Position = provenance.Anchor, provenance.Position
+				}
+				s.problems = append(s.problems, problem)
+			}
+		}
 	}
 
-	return nil
+	return declareAliases(as)
 }
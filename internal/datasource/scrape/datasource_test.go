@@ -0,0 +1,188 @@
+package scrape
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+// scrapeFixture scrapes the page where the table of `Chat` has the row with too many columns
+// and the hyphenated heading `formatting-options` is between types and methods.
+func scrapeFixture(t *testing.T, lenient bool) (*Scraper, *spec.ApiSpec, error) {
+	t.Helper()
+
+	s, err := NewFileScraper("testdata/api.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetLenient(lenient)
+	as, err := spec.NewApiSpec(s)
+
+	return s, as, err
+}
+
+func TestLenientScraping(t *testing.T) {
+	s, as, err := scrapeFixture(t, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	problems := s.GetProblems()
+	if len(problems) != 2 {
+		t.Fatalf("got problems %v, want 2", problems)
+	}
+
+	tests := []struct {
+		problem  Problem
+		code     ProblemCode
+		severity spec.Severity
+		anchor   string
+		position int
+		row      int
+		snippet  string
+	}{
+		{problems[0], CodeUnknownColumns, spec.SeverityError, "chat", 7, 2, "<td>Stray cell</td>"},
+		{problems[1], CodeSkippedHeading, spec.SeverityInfo, "formatting-options", 8, 0, "Formatting options</h4>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			p := tt.problem
+			if p.Code != tt.code || p.Severity != tt.severity {
+				t.Errorf("got %s %s, want %s %s", p.Severity, p.Code, tt.severity, tt.code)
+			}
+			if p.Anchor != tt.anchor || p.Position != tt.position || p.Row != tt.row {
+				t.Errorf("got location %s, want #%s, node %d, row %d", p.Location(), tt.anchor, tt.position, tt.row)
+			}
+			if !strings.Contains(p.Snippet, tt.snippet) {
+				t.Errorf("snippet %q doesn't contain %q", p.Snippet, tt.snippet)
+			}
+		})
+	}
+
+	if !strings.HasPrefix(problems[0].Snippet, "<tr>") || strings.Contains(problems[0].Snippet, "title") {
+		t.Errorf("snippet isn't the broken row: %q", problems[0].Snippet)
+	}
+
+	chat, _ := as.GetType("Chat")
+	var properties []string
+	for _, p := range chat.GetProperties() {
+		properties = append(properties, p.GetName())
+	}
+	if strings.Join(properties, ",") != "id,title" {
+		t.Errorf("got properties %v, want the broken row skipped", properties)
+	}
+	if _, exists := as.GetMethod("sendMessage"); !exists {
+		t.Error("scraping isn't continued after the problem")
+	}
+}
+
+func TestStrictScraping(t *testing.T) {
+	s, _, err := scrapeFixture(t, false)
+
+	problems := s.GetProblems()
+	if len(problems) != 1 || problems[0].Code != CodeUnknownColumns {
+		t.Fatalf("expected the only problem of the broken row, got %v", problems)
+	}
+	if err == nil || !strings.Contains(err.Error(), "#chat, node 7, row 2") {
+		t.Errorf("expected the error of the broken row, got %v", err)
+	}
+}
+
+func TestProvenance(t *testing.T) {
+	_, as, err := scrapeFixture(t, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chat, _ := as.GetType("Chat")
+	sendMessage, _ := as.GetMethod("sendMessage")
+
+	tests := []struct {
+		name       string
+		provenance *spec.Provenance
+		want       spec.Provenance
+	}{
+		{
+			name:       "type",
+			provenance: chat.GetProvenance(),
+			want:       spec.Provenance{Anchor: "chat", Heading: "Chat", Position: 5},
+		},
+		{
+			name:       "property",
+			provenance: chat.GetProperties()[1].GetProvenance(),
+			want: spec.Provenance{Anchor: "chat", Heading: "Chat", Position: 7, Row: 3, Cells: []string{
+				"title",
+				"String",
+				"<em>Optional</em>. Title, for supergroups, channels and group chats",
+			}},
+		},
+		{
+			name:       "method",
+			provenance: sendMessage.GetProvenance(),
+			want:       spec.Provenance{Anchor: "sendmessage", Heading: "sendMessage", Position: 11},
+		},
+		{
+			name:       "argument",
+			provenance: sendMessage.GetArguments()[0].GetProvenance(),
+			want: spec.Provenance{Anchor: "sendmessage", Heading: "sendMessage", Position: 13, Row: 1, Cells: []string{
+				"chat_id",
+				"Integer or String",
+				"Yes",
+				"Unique identifier for the target chat or username of the target channel (in the format <code>@channelusername</code>)",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.provenance == nil {
+				t.Fatal("provenance not recorded")
+			}
+			if !reflect.DeepEqual(*tt.provenance, tt.want) {
+				t.Errorf("got %+v, want %+v", *tt.provenance, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultipleParents(t *testing.T) {
+	s, err := NewFileScraper("testdata/parents.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	as, err := spec.NewApiSpec(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message, _ := as.GetType("Message")
+	var parents []string
+	for _, p := range message.GetParents() {
+		parents = append(parents, p.GetName())
+	}
+	sort.Strings(parents)
+	if strings.Join(parents, ",") != "MaybeInaccessibleMessage,ReplyTarget" {
+		t.Errorf("got parents %v, want MaybeInaccessibleMessage and ReplyTarget", parents)
+	}
+
+	for name, want := range map[string]string{"MaybeInaccessibleMessage": "Message,InaccessibleMessage", "ReplyTarget": "Message"} {
+		parent, _ := as.GetType(name)
+		var children []string
+		for _, c := range parent.GetChildren() {
+			children = append(children, c.GetName())
+		}
+		if strings.Join(children, ",") != want {
+			t.Errorf("got children %v of %s, want %s", children, name, want)
+		}
+	}
+
+	for _, d := range as.Diagnose() {
+		if d.Code == spec.CodeAsymmetricRelation {
+			t.Errorf("unexpected diagnostic: %s", d)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package scrape
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+var (
+	enumStartRegexp   = regexp.MustCompile(`(?i)\b(?:can be(?: either)?|(?:must be )?one of|either)\s*:?\s*(“.*)`)
+	enumValueRegexp   = regexp.MustCompile(`“([^”]+)”`)
+	sentenceEndRegexp = regexp.MustCompile(`\.\s+[A-Z]`)
+
+	lengthRangeRegexp   = regexp.MustCompile(`(?i)\b(\d+)\s*[-–]\s*(\d+) (?:characters|bytes|symbols)\b`)
+	lengthBetweenRegexp = regexp.MustCompile(`(?i)\bbetween (\d+) and (\d+) (?:characters|bytes|symbols)\b`)
+	lengthUpToRegexp    = regexp.MustCompile(`(?i)\b(?:up to|at most|maximum) (\d+) (?:characters|bytes|symbols)\b`)
+	valueRangeRegexp    = regexp.MustCompile(`(?i)\bvalues? between (-?\d+(?:\.\d+)?)\s*[-–]\s*(-?\d+(?:\.\d+)?)`)
+	valueBetweenRegexp  = regexp.MustCompile(`(?i)\b(?:must|should) be between (-?\d+(?:\.\d+)?) and (-?\d+(?:\.\d+)?)\b( (?:characters|bytes|symbols)\b)?`)
+
+	requiredIfAbsentRegexp  = regexp.MustCompile(`(?i)\brequired if ([a-z_]+(?:(?:, | and | or )[a-z_]+)*) (?:is|are) not specified`)
+	mutuallyExclusiveRegexp = regexp.MustCompile(`(?i)\b(?:can be specified instead of|can't be used with|cannot be used with|mutually exclusive with) ([a-z_]+)`)
+	argumentsListRegexp     = regexp.MustCompile(`, | and | or `)
+
+	defaultRegexp = regexp.MustCompile(`(?i)\bdefaults to (“[^”]+”|-?\d+(?:\.\d+)?\b|true\b|false\b)`)
+)
+
+// extractEnum extracts the list of values from phrases like `can be either “private”, “group” or “channel”`.
+// Values are extracted only if the data type is or contains string.
+func extractEnum(text string, dataTypes []spec.DataTypeDefinition) []string {
+	if !hasStringDataType(dataTypes) {
+		return nil
+	}
+
+	matches := enumStartRegexp.FindStringSubmatch(text)
+	if len(matches) < 2 {
+		return nil
+	}
+
+	sentence := matches[1]
+	if loc := sentenceEndRegexp.FindStringIndex(sentence); loc != nil {
+		sentence = sentence[:loc[0]]
+	}
+
+	var values []string
+	seen := make(map[string]bool)
+	for _, m := range enumValueRegexp.FindAllStringSubmatch(sentence, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			values = append(values, m[1])
+		}
+	}
+
+	return values
+}
+
+func hasStringDataType(dataTypes []spec.DataTypeDefinition) bool {
+	for _, dt := range spec.FlattenDataTypes(dataTypes) {
+		if scalar, ok := dt.(*spec.ScalarDataType); ok && scalar.GetDefinition() == "string" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// extractConstraints extracts length limits (e.g. `1-4096 characters`) and value limits (e.g. `Values between 1-100 are accepted`).
+// Both limits can be found in the same text. Value limits require wording like `must be between 5 and 600`,
+// so numbers mentioned in other sentences aren't taken for limits.
+func extractConstraints(text string) *spec.ValueConstraints {
+	constraints := &spec.ValueConstraints{}
+
+	if matches := lengthRangeRegexp.FindStringSubmatch(text); len(matches) == 3 {
+		constraints.MinLength, constraints.MaxLength = parseInt(matches[1]), parseInt(matches[2])
+	} else if matches := lengthBetweenRegexp.FindStringSubmatch(text); len(matches) == 3 {
+		constraints.MinLength, constraints.MaxLength = parseInt(matches[1]), parseInt(matches[2])
+	} else if matches := lengthUpToRegexp.FindStringSubmatch(text); len(matches) == 2 {
+		constraints.MaxLength = parseInt(matches[1])
+	}
+
+	if matches := valueRangeRegexp.FindStringSubmatch(text); len(matches) == 3 {
+		constraints.Minimum, constraints.Maximum = parseFloat(matches[1]), parseFloat(matches[2])
+	} else if matches := valueBetweenRegexp.FindStringSubmatch(text); len(matches) == 4 && matches[3] == "" {
+		constraints.Minimum, constraints.Maximum = parseFloat(matches[1]), parseFloat(matches[2])
+	}
+
+	if constraints.IsEmpty() {
+		return nil
+	}
+
+	return constraints
+}
+
+func parseInt(s string) *int {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return nil
+	}
+
+	return &v
+}
+
+func parseFloat(s string) *float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil
+	}
+
+	return &v
+}
+
+// extractDefault extracts the default value from phrases like `Defaults to 100` or `Defaults to “HTML”`.
+// Quoted values are accepted only for string data types, others only for numeric and boolean ones.
+func extractDefault(text string, dataTypes []spec.DataTypeDefinition) interface{} {
+	matches := defaultRegexp.FindStringSubmatch(text)
+	if len(matches) != 2 {
+		return nil
+	}
+
+	raw := matches[1]
+	quoted := strings.HasPrefix(raw, "“")
+	raw = strings.TrimSuffix(strings.TrimPrefix(raw, "“"), "”")
+
+	for _, dt := range spec.FlattenDataTypes(dataTypes) {
+		scalar, ok := dt.(*spec.ScalarDataType)
+		if !ok || (scalar.GetDefinition() == "string") != quoted {
+			continue
+		}
+
+		if value, err := scalar.ParseValue(raw); err == nil {
+			return value
+		}
+	}
+
+	return nil
+}
+
+// resolveReturnConditions finds arguments of the method mentioned in conditions of return types.
+// An argument is mentioned by its name or by its name without the `_id` suffix (e.g. `inline message` for `inline_message_id`).
+// Mentions which are a part of longer mentions are skipped, so `message` doesn't point to `message_id` in `inline message`.
+// The "otherwise" data type depends on the arguments of all the other conditions.
+func resolveReturnConditions(m *spec.TgMethodSpec) {
+	var conditions []string
+	for _, cr := range m.GetConditionalReturns() {
+		if cr.GetCondition() != "otherwise" {
+			conditions = append(conditions, strings.ToLower(cr.GetCondition()))
+		}
+	}
+
+	for _, cr := range m.GetConditionalReturns() {
+		condition := strings.ToLower(cr.GetCondition())
+		if condition == "otherwise" {
+			condition = strings.Join(conditions, "; ")
+		}
+
+		mentions := make(map[string]string)
+		for _, a := range m.GetArguments() {
+			for _, mention := range []string{a.GetName(), strings.ReplaceAll(strings.TrimSuffix(a.GetName(), "_id"), "_", " ")} {
+				if strings.Contains(condition, mention) {
+					mentions[a.GetName()] = mention
+					break
+				}
+			}
+		}
+
+		var names []string
+		for name, mention := range mentions {
+			nested := false
+			for other, otherMention := range mentions {
+				if other != name && len(otherMention) > len(mention) && strings.Contains(otherMention, mention) {
+					nested = true
+					break
+				}
+			}
+			if !nested {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		cr.SetArguments(names)
+	}
+}
+
+// extractRequiredIfAbsent extracts names of arguments from phrases like `Required if chat_id and message_id are not specified`.
+func extractRequiredIfAbsent(text string) []string {
+	matches := requiredIfAbsentRegexp.FindStringSubmatch(text)
+	if len(matches) != 2 {
+		return nil
+	}
+
+	return argumentsListRegexp.Split(matches[1], -1)
+}
+
+// extractMutuallyExclusive extracts names of arguments from phrases like `which can be specified instead of parse_mode`.
+func extractMutuallyExclusive(text string) []string {
+	var names []string
+	for _, m := range mutuallyExclusiveRegexp.FindAllStringSubmatch(text, -1) {
+		names = append(names, m[1])
+	}
+
+	return names
+}
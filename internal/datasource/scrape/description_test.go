@@ -0,0 +1,272 @@
+package scrape
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+// declare returns the data types of the definitions declared in the empty spec.
+func declare(t *testing.T, definitions ...string) []spec.DataTypeDefinition {
+	t.Helper()
+
+	as := spectest.New(t, func(as *spec.ApiSpec) error {
+		return nil
+	})
+
+	var dataTypes []spec.DataTypeDefinition
+	for _, definition := range definitions {
+		dataTypes = append(dataTypes, as.DeclareDataType(definition))
+	}
+
+	return dataTypes
+}
+
+func TestExtractEnum(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		types []string
+		want  []string
+	}{
+		{
+			name:  "can be either",
+			text:  "Type of the chat, can be either “private”, “group”, “supergroup” or “channel”",
+			types: []string{"string"},
+			want:  []string{"private", "group", "supergroup", "channel"},
+		},
+		{
+			name:  "values with explanations",
+			text:  "Type of the entity. Currently, can be “mention” (@username), “hashtag” (#hashtag), “cashtag” ($USD) or “bot_command” (/start@jobs_bot)",
+			types: []string{"string"},
+			want:  []string{"mention", "hashtag", "cashtag", "bot_command"},
+		},
+		{
+			name:  "quotes of the next sentence",
+			text:  "Type of the sticker, currently one of “regular”, “mask”, “custom_emoji”. The type of the sticker is independent from its format, which is determined by the fields “is_animated” and “is_video”.",
+			types: []string{"string"},
+			want:  []string{"regular", "mask", "custom_emoji"},
+		},
+		{
+			name:  "repeated values",
+			text:  "Poll type, currently can be “regular” or “quiz”, defaults to “regular”",
+			types: []string{"string"},
+			want:  []string{"regular", "quiz"},
+		},
+		{
+			name:  "union with string",
+			text:  "Unique identifier of the chat, can be either “@channelusername” or “@supergroupusername”",
+			types: []string{"int64|string"},
+			want:  []string{"@channelusername", "@supergroupusername"},
+		},
+		{
+			name:  "values without quotes",
+			text:  "Type of action to broadcast. Choose one, depending on what the user is about to receive: typing for text messages, upload_photo for photos",
+			types: []string{"string"},
+		},
+		{
+			name:  "integer",
+			text:  "Value of the dice, can be either “1”, “2” or “3”",
+			types: []string{"int32"},
+		},
+		{
+			name:  "boolean",
+			text:  "Pass True if the message must be sent, can be either “true” or “false”",
+			types: []string{"boolean"},
+		},
+		{
+			name:  "array of strings",
+			text:  "List of the update types, can be either “message” or “edited_channel_post”",
+			types: []string{"array<string>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractEnum(tt.text, declare(t, tt.types...)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestExtractConstraints(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want *spec.ValueConstraints
+	}{
+		{
+			name: "length range",
+			text: "Text of the message to be sent, 1-4096 characters after entities parsing",
+			want: &spec.ValueConstraints{MinLength: intPtr(1), MaxLength: intPtr(4096)},
+		},
+		{
+			name: "length up to",
+			text: "Bot description, up to 512 characters",
+			want: &spec.ValueConstraints{MaxLength: intPtr(512)},
+		},
+		{
+			name: "length between",
+			text: "Short name of the sticker set, must be between 1 and 64 characters",
+			want: &spec.ValueConstraints{MinLength: intPtr(1), MaxLength: intPtr(64)},
+		},
+		{
+			name: "value range",
+			text: "Limits the number of updates to be retrieved. Values between 1-100 are accepted. Defaults to 100.",
+			want: &spec.ValueConstraints{Minimum: floatPtr(1), Maximum: floatPtr(100)},
+		},
+		{
+			name: "value between",
+			text: "Amount of time in seconds the poll will be active after creation, must be between 5 and 600",
+			want: &spec.ValueConstraints{Minimum: floatPtr(5), Maximum: floatPtr(600)},
+		},
+		{
+			name: "length and value",
+			text: "Custom title, 0-16 characters. Values between 1-100 are accepted.",
+			want: &spec.ValueConstraints{MinLength: intPtr(0), MaxLength: intPtr(16), Minimum: floatPtr(1), Maximum: floatPtr(100)},
+		},
+		{
+			name: "numbers in prose",
+			text: "Messages sent between 5 and 10 minutes ago can be edited only once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractConstraints(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %s, want %s", formatConstraints(got), formatConstraints(tt.want))
+			}
+		})
+	}
+}
+
+func formatConstraints(c *spec.ValueConstraints) string {
+	if c == nil {
+		return "no constraints"
+	}
+
+	format := func(name string, value interface{}) string {
+		switch v := value.(type) {
+		case *int:
+			if v != nil {
+				return fmt.Sprintf(" %s=%d", name, *v)
+			}
+		case *float64:
+			if v != nil {
+				return fmt.Sprintf(" %s=%g", name, *v)
+			}
+		}
+
+		return ""
+	}
+
+	return "{" + format("minLength", c.MinLength) + format("maxLength", c.MaxLength) + format("minimum", c.Minimum) + format("maximum", c.Maximum) + " }"
+}
+
+func TestExtractDefault(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		types []string
+		want  interface{}
+	}{
+		{"integer", "Limits the number of updates to be retrieved. Values between 1-100 are accepted. Defaults to 100.", []string{"int32"}, int64(100)},
+		{"zero", "Timeout in seconds for long polling. Defaults to 0, i.e. usual short polling.", []string{"int32"}, int64(0)},
+		{"string", "Mode for parsing entities in the message text. Defaults to “HTML”", []string{"string"}, "HTML"},
+		{"boolean", "Pass True to send the message silently. Defaults to true", []string{"boolean"}, true},
+		{"float", "Width of the area, defaults to 0.5", []string{"float"}, 0.5},
+		{"quoted value of integer", "Offset of the first update. Defaults to “100”", []string{"int32"}, nil},
+		{"number of string", "Mode for parsing entities. Defaults to 100", []string{"string"}, nil},
+		{"boolean of integer", "Defaults to false", []string{"int32"}, nil},
+		{"no default", "Unique identifier for the target chat", []string{"int64|string"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractDefault(tt.text, declare(t, tt.types...)); got != tt.want {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveReturnConditions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "edited message",
+			text: "On success, if the edited message is not an inline message, the edited Message is returned, otherwise True is returned.",
+			want: []string{"Message if the edited message is not an inline message: inline_message_id", "true otherwise: inline_message_id"},
+		},
+		{
+			name: "stopped live location",
+			text: "On success, if the message is not an inline message, the edited Message is returned, otherwise True is returned.",
+			want: []string{"Message if the message is not an inline message: inline_message_id", "true otherwise: inline_message_id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			spectest.New(t, func(as *spec.ApiSpec) error {
+				m := spectest.AddMethod(as, "editMessageText", "", "chat_id int64|string", "message_id int32", "inline_message_id string", "text string")
+				returnType, conditionalReturns := extractReturnTypes(tt.text, createHelper(as, false))
+				m.AddReturnType(returnType)
+				for _, cr := range conditionalReturns {
+					m.AddConditionalReturn(cr)
+				}
+
+				resolveReturnConditions(m)
+				for _, cr := range m.GetConditionalReturns() {
+					got = append(got, cr.String()+": "+strings.Join(cr.GetArguments(), ","))
+				}
+
+				return nil
+			})
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSeveralReturnConditions(t *testing.T) {
+	spectest.New(t, func(as *spec.ApiSpec) error {
+		m := spectest.AddMethod(as, "getGameHighScores", "", "user_id int64", "chat_id int64", "inline_message_id string")
+		for _, c := range []struct{ definition, condition string }{
+			{"Message", "chat_id is passed"},
+			{"string", "inline_message_id is passed"},
+			{"true", "otherwise"},
+		} {
+			cr, _ := spec.NewTgMethodSpecConditionalReturn(as.DeclareDataType(c.definition), c.condition)
+			m.AddConditionalReturn(cr)
+		}
+
+		// The result must not depend on the order of iteration, so it's checked several times.
+		for i := 0; i < 20; i++ {
+			resolveReturnConditions(m)
+			if got := m.GetConditionalReturns()[2].GetArguments(); !reflect.DeepEqual(got, []string{"chat_id", "inline_message_id"}) {
+				t.Fatalf("got %v for the otherwise data type", got)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,235 @@
+package scrape
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+const defaultUserAgent string = "tg-bot-api-spec (+https://github.com/alserom/tg-bot-api-spec)"
+
+// FetchOptions configures fetching of pages by the scraper. Zero values mean: official site, http.DefaultClient,
+// timeout of the client, no retries, default User-Agent and no cache.
+type FetchOptions struct {
+	// BaseUrl replaces https://core.telegram.org for fetching, e.g. for a local mirror. Links of the spec stay official.
+	BaseUrl string
+	Client  *http.Client
+	// Timeout is applied only if the Client isn't provided, the provided one keeps its own timeout.
+	Timeout time.Duration
+	// Retries is the number of extra attempts after network errors, 5xx and 429 responses.
+	// The delay before the attempt is taken from the Retry-After header, otherwise it starts from Backoff and doubles every time.
+	Retries   int
+	Backoff   time.Duration
+	UserAgent string
+	// CacheDir keeps fetched pages. Cached pages are revalidated by ETag and Last-Modified, so unchanged pages aren't downloaded.
+	CacheDir string
+}
+
+func DefaultFetchOptions() FetchOptions {
+	return FetchOptions{Timeout: 30 * time.Second, Retries: 2, Backoff: time.Second}
+}
+
+type fetcher struct {
+	options FetchOptions
+	client  *http.Client
+	// problems are warnings of fetching, e.g. the cache which can't be written.
+	problems Problems
+}
+
+func newFetcher(options FetchOptions) *fetcher {
+	if options.BaseUrl == "" {
+		options.BaseUrl = url
+	}
+	options.BaseUrl = strings.TrimSuffix(options.BaseUrl, "/")
+
+	if options.UserAgent == "" {
+		options.UserAgent = defaultUserAgent
+	}
+
+	client := options.Client
+	if client == nil {
+		client = &http.Client{Timeout: options.Timeout}
+	}
+
+	return &fetcher{options: options, client: client}
+}
+
+// cacheEntry is the metadata of the cached page, the page itself is kept next to it.
+type cacheEntry struct {
+	Url          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	// Checksum is the SHA-256 of the page, so the damaged page isn't used.
+	Checksum string `json:"checksum"`
+}
+
+// fetch fetches the page by the official URL, e.g. https://core.telegram.org/bots/api, from the base URL of the options.
+func (f *fetcher) fetch(officialUrl string) (*goquery.Document, error) {
+	u := f.options.BaseUrl + strings.TrimPrefix(officialUrl, url)
+
+	var cached *cacheEntry
+	var cachedBody []byte
+	if f.options.CacheDir != "" {
+		cached, cachedBody = f.readCache(u)
+	}
+
+	var res *http.Response
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", f.options.UserAgent)
+		if cached != nil {
+			if cached.ETag != "" {
+				req.Header.Set("If-None-Match", cached.ETag)
+			}
+			if cached.LastModified != "" {
+				req.Header.Set("If-Modified-Since", cached.LastModified)
+			}
+		}
+
+		res, err = f.client.Do(req)
+		retryable := err != nil || res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests
+		if !retryable || attempt >= f.options.Retries {
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		delay := f.options.Backoff << attempt
+		if res != nil {
+			if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+				delay = retryAfter
+			}
+			res.Body.Close()
+		}
+		time.Sleep(delay)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotModified && cached != nil {
+		return goquery.NewDocumentFromReader(bytes.NewReader(cachedBody))
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("Get \"%s\": %s", u, res.Status))
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pages without validators can't be revalidated, so they aren't cached. The page which isn't cached is still returned.
+	entry := &cacheEntry{Url: u, ETag: res.Header.Get("ETag"), LastModified: res.Header.Get("Last-Modified"), Checksum: checksum(body)}
+	if f.options.CacheDir != "" && (entry.ETag != "" || entry.LastModified != "") {
+		if err := f.writeCache(entry, body); err != nil {
+			f.problems = append(f.problems, Problem{
+				Code:     CodeCacheNotWritten,
+				Severity: spec.SeverityWarning,
+				Message:  fmt.Sprintf("page %s isn't cached: %s", u, err.Error()),
+			})
+		}
+	}
+
+	return goquery.NewDocumentFromReader(bytes.NewReader(body))
+}
+
+// parseRetryAfter returns the delay of the Retry-After header, which is either seconds or the HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if delay := time.Until(date); delay > 0 {
+		return delay, true
+	}
+
+	return 0, true
+}
+
+func (f fetcher) cachePath(u string) string {
+	return filepath.Join(f.options.CacheDir, checksum([]byte(u)))
+}
+
+// readCache returns nil if the page isn't cached or the cache is broken, so the page is fetched again.
+func (f fetcher) readCache(u string) (*cacheEntry, []byte) {
+	path := f.cachePath(u)
+
+	meta, err := os.ReadFile(path + ".json")
+	if err != nil {
+		return nil, nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(meta, &entry); err != nil || entry.Url != u {
+		return nil, nil
+	}
+
+	body, err := os.ReadFile(path + ".html")
+	if err != nil || checksum(body) != entry.Checksum {
+		return nil, nil
+	}
+
+	return &entry, body
+}
+
+// writeCache writes the page before the metadata, so the metadata never describes a page which isn't written.
+func (f fetcher) writeCache(entry *cacheEntry, body []byte) error {
+	if err := os.MkdirAll(f.options.CacheDir, 0755); err != nil {
+		return err
+	}
+
+	path := f.cachePath(entry.Url)
+	os.Remove(path + ".json")
+
+	if err := writeFileAtomically(path+".html", body); err != nil {
+		return err
+	}
+
+	meta, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomically(path+".json", meta)
+}
+
+func checksum(content []byte) string {
+	hash := sha256.Sum256(content)
+
+	return hex.EncodeToString(hash[:])
+}
+
+func writeFileAtomically(path string, content []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,242 @@
+package scrape
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+const testPage = `<html><body><div id="dev_page_content"><h3>Bot API</h3></div></body></html>`
+
+// testServer serves the page under /bots/api with the ETag. The first `failures` requests get 503.
+type testServer struct {
+	*httptest.Server
+	failures    int32
+	requests    int32
+	downloads   int32
+	conditional int32
+}
+
+func newTestServer(t *testing.T, failures int32) *testServer {
+	t.Helper()
+
+	ts := &testServer{failures: failures}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&ts.requests, 1) <= ts.failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		if r.Header.Get("If-None-Match") != "" {
+			atomic.AddInt32(&ts.conditional, 1)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+
+		atomic.AddInt32(&ts.downloads, 1)
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(testPage))
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func (ts *testServer) options(cacheDir string) FetchOptions {
+	return FetchOptions{BaseUrl: ts.URL, Retries: 2, Backoff: time.Millisecond, CacheDir: cacheDir}
+}
+
+func fetchTitle(t *testing.T, options FetchOptions) string {
+	t.Helper()
+
+	doc, err := newFetcher(options).fetch(url_api_doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return doc.Find("#dev_page_content h3").Text()
+}
+
+func TestFetchRetries(t *testing.T) {
+	ts := newTestServer(t, 2)
+
+	if title := fetchTitle(t, ts.options("")); title != "Bot API" {
+		t.Errorf("unexpected page title '%s'", title)
+	}
+	if ts.requests != 3 {
+		t.Errorf("expected 3 requests, got %d", ts.requests)
+	}
+}
+
+func TestFetchRetriesExhausted(t *testing.T) {
+	ts := newTestServer(t, 3)
+
+	_, err := newFetcher(ts.options("")).fetch(url_api_doc)
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("expected the 503 error, got %v", err)
+	}
+	if ts.requests != 3 {
+		t.Errorf("expected 3 requests, got %d", ts.requests)
+	}
+}
+
+func TestFetchRevalidation(t *testing.T) {
+	ts := newTestServer(t, 0)
+	options := ts.options(t.TempDir())
+
+	for i := 0; i < 2; i++ {
+		if title := fetchTitle(t, options); title != "Bot API" {
+			t.Errorf("fetch #%d: unexpected page title '%s'", i+1, title)
+		}
+	}
+
+	if ts.downloads != 1 || ts.conditional != 1 {
+		t.Errorf("expected 1 download and 1 revalidation, got %d and %d", ts.downloads, ts.conditional)
+	}
+}
+
+func TestFetchCorruptedCache(t *testing.T) {
+	corruptions := map[string]func(path string) error{
+		"broken metadata": func(path string) error {
+			return os.WriteFile(path+".json", []byte("{"), 0644)
+		},
+		"damaged page": func(path string) error {
+			return os.WriteFile(path+".html", []byte("<html>"), 0644)
+		},
+		"missing page": func(path string) error {
+			return os.Remove(path + ".html")
+		},
+	}
+
+	for name, corrupt := range corruptions {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestServer(t, 0)
+			options := ts.options(t.TempDir())
+			fetchTitle(t, options)
+
+			f := newFetcher(options)
+			if err := corrupt(f.cachePath(f.options.BaseUrl + strings.TrimPrefix(url_api_doc, url))); err != nil {
+				t.Fatal(err)
+			}
+
+			if title := fetchTitle(t, options); title != "Bot API" {
+				t.Errorf("unexpected page title '%s'", title)
+			}
+			if ts.downloads != 2 || ts.conditional != 0 {
+				t.Errorf("expected 2 downloads without revalidation, got %d downloads and %d revalidations", ts.downloads, ts.conditional)
+			}
+
+			entries, _ := filepath.Glob(filepath.Join(options.CacheDir, "*"))
+			if len(entries) != 2 {
+				t.Errorf("expected the page and the metadata in the cache, got %v", entries)
+			}
+		})
+	}
+}
+
+func TestFetchKeepsClientTimeout(t *testing.T) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	options := DefaultFetchOptions()
+	options.Client = client
+
+	if f := newFetcher(options); f.client != client || client.Timeout != 5*time.Second {
+		t.Errorf("the provided client is changed, timeout is %s", f.client.Timeout)
+	}
+
+	if f := newFetcher(DefaultFetchOptions()); f.client.Timeout != 30*time.Second {
+		t.Errorf("expected the default timeout, got %s", f.client.Timeout)
+	}
+}
+
+func TestFetchWithUnwritableCache(t *testing.T) {
+	ts := newTestServer(t, 0)
+	// The cache dir can't be created over the file.
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	if err := os.WriteFile(cacheDir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f := newFetcher(ts.options(cacheDir))
+	doc, err := f.fetch(url_api_doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title := doc.Find("#dev_page_content h3").Text(); title != "Bot API" {
+		t.Errorf("unexpected page title '%s'", title)
+	}
+
+	if len(f.problems) != 1 || f.problems[0].Code != CodeCacheNotWritten || f.problems[0].Severity != spec.SeverityWarning {
+		t.Errorf("expected the warning of the cache, got %v", f.problems)
+	}
+}
+
+func TestFetchRetryAfter(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(testPage))
+	}))
+	t.Cleanup(ts.Close)
+
+	// The backoff would make the test wait for a minute.
+	options := FetchOptions{BaseUrl: ts.URL, Retries: 1, Backoff: time.Minute}
+	done := make(chan string)
+	go func() {
+		doc, err := newFetcher(options).fetch(url_api_doc)
+		if err != nil {
+			done <- err.Error()
+			return
+		}
+		done <- doc.Find("#dev_page_content h3").Text()
+	}()
+
+	select {
+	case title := <-done:
+		if title != "Bot API" {
+			t.Errorf("unexpected result '%s'", title)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Retry-After is ignored")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("got %s, %t, want %s, %t", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if got, ok := parseRetryAfter(date); !ok || got < 59*time.Minute || got > time.Hour {
+		t.Errorf("got %s, %t for the date in an hour", got, ok)
+	}
+}
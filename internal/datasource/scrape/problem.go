@@ -0,0 +1,128 @@
+package scrape
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+type ProblemCode string
+
+const (
+	CodeMissingAnchor     ProblemCode = "missing-anchor"
+	CodeInvalidItem       ProblemCode = "invalid-item"
+	CodeSkippedHeading    ProblemCode = "skipped-heading"
+	CodeUnparsedDataType  ProblemCode = "unparsed-data-type"
+	CodeUnknownColumns    ProblemCode = "unknown-column-count"
+	CodeInvalidRow        ProblemCode = "invalid-row"
+	CodeMissingReturnType ProblemCode = "missing-return-type"
+	CodeUnknownChild      ProblemCode = "unknown-child"
+	CodeCacheNotWritten   ProblemCode = "cache-not-written"
+)
+
+const maxSnippetLength = 500
+
+// Problem is the anomaly of the page. Problems with the error severity stop the scraper unless it's lenient.
+// Position is the number of the node in the page content (the same as the order of elements), Row is the number of the table row.
+type Problem struct {
+	Code     ProblemCode   `json:"code"`
+	Severity spec.Severity `json:"severity"`
+	Message  string        `json:"message"`
+	Anchor   string        `json:"anchor,omitempty"`
+	Position int           `json:"position,omitempty"`
+	Row      int           `json:"row,omitempty"`
+	Snippet  string        `json:"snippet,omitempty"`
+}
+
+func (p Problem) Error() string {
+	if location := p.Location(); location != "" {
+		return p.Message + " (at " + location + ")"
+	}
+
+	return p.Message
+}
+
+// Location returns the position like `#sendmessage, node 123, row 4` or an empty string if it's unknown.
+func (p Problem) Location() string {
+	var parts []string
+	if p.Anchor != "" {
+		parts = append(parts, "#"+p.Anchor)
+	}
+	if p.Position != 0 {
+		parts = append(parts, fmt.Sprintf("node %d", p.Position))
+	}
+	if p.Row != 0 {
+		parts = append(parts, fmt.Sprintf("row %d", p.Row))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+type Problems []Problem
+
+// Filter returns problems of the provided severity.
+func (ps Problems) Filter(severity spec.Severity) Problems {
+	var filtered Problems
+	for _, p := range ps {
+		if p.Severity == severity {
+			filtered = append(filtered, p)
+		}
+	}
+
+	return filtered
+}
+
+func (ps Problems) Errors() Problems {
+	return ps.Filter(spec.SeverityError)
+}
+
+func (ps Problems) Warnings() Problems {
+	return ps.Filter(spec.SeverityWarning)
+}
+
+func (ps Problems) HasErrors() bool {
+	return len(ps.Errors()) > 0
+}
+
+// newProblem takes the position and the snippet from the node. Nodes inside tables get the row too.
+func newProblem(code ProblemCode, severity spec.Severity, node *goquery.Selection, format string, args ...interface{}) *Problem {
+	p := &Problem{Code: code, Severity: severity, Message: fmt.Sprintf(format, args...)}
+	if node == nil || node.Length() == 0 {
+		return p
+	}
+
+	top := node.ParentsUntil("#dev_page_content").Last()
+	if top.Length() == 0 || goquery.NodeName(top) == "html" {
+		top = node
+	}
+	p.Position = top.Index() + 1
+
+	// The anchor is the one of the closest heading, which is the top node itself for problems of headings.
+	heading := top
+	if name := goquery.NodeName(top); name != "h3" && name != "h4" {
+		heading = top.PrevAllFiltered("h3, h4").First()
+	}
+	p.Anchor = heading.Find("a.anchor").AttrOr("name", "")
+
+	if tr := node.Closest("tr"); tr.Length() != 0 {
+		p.Row = tr.Index() + 1
+		node = tr
+	}
+
+	if html, err := goquery.OuterHtml(node); err == nil {
+		p.Snippet = strings.TrimSpace(html)
+		if len(p.Snippet) > maxSnippetLength {
+			n := maxSnippetLength
+			for n > 0 && !utf8.RuneStart(p.Snippet[n]) {
+				n--
+			}
+			p.Snippet = p.Snippet[:n] + "..."
+		}
+	}
+
+	return p
+}
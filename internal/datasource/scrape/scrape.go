@@ -1,7 +1,6 @@
 package scrape
 
 import (
-	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -19,6 +18,17 @@ func scrape(doc *goquery.Document, h helper) chan interface{} {
 		defer close(ch)
 		var category string
 		var item interface{}
+		var itemNode *goquery.Selection
+
+		flush := func() {
+			if method, ok := item.(*spec.TgMethodSpec); ok && len(method.GetReturnTypes()) == 0 {
+				h.report(ch, newProblem(CodeMissingReturnType, spec.SeverityWarning, itemNode, "method '%s' has no detected return type", method.GetName()))
+			}
+			if item != nil {
+				ch <- item
+			}
+			item = nil
+		}
 
 		doc.Find("#dev_page_content").Children().EachWithBreak(func(i int, s *goquery.Selection) bool {
 			nodeName := goquery.NodeName(s)
@@ -27,23 +37,34 @@ func scrape(doc *goquery.Document, h helper) chan interface{} {
 				anchor := s.Find("a.anchor")
 				anchorName, exists := anchor.Attr("name")
 				if !exists {
-					ch <- errors.New(fmt.Sprintf("scraping error: detected node %s without anchor", nodeName))
-					return false
+					// The content of the heading doesn't belong to the previous item.
+					flush()
+					return h.report(ch, newProblem(CodeMissingAnchor, spec.SeverityError, s, "scraping error: detected node %s without anchor", nodeName))
 				}
 
-				if item != nil {
-					ch <- item
-				}
+				flush()
 
 				if nodeName == "h3" {
 					category = anchorName
-					item = nil
 				} else {
 					var err error
-					item, err = newSpecItem(category, anchorName, s.Text(), anchor.AttrOr("href", ""))
+					item, err = newSpecItem(category, anchorName, s.Text(), anchor.AttrOr("href", ""), i+1)
+					itemNode = s
 					if err != nil {
-						ch <- errors.New("scraping error: can't create new item. error: " + err.Error())
-						return false
+						// The content up to the next heading is skipped, as there's no item to fill.
+						item, itemNode = nil, nil
+						return h.report(ch, newProblem(CodeInvalidItem, spec.SeverityError, s, "scraping error: can't create new item. error: %s", err.Error()))
+					}
+
+					provenance := &spec.Provenance{Anchor: anchorName, Heading: strings.TrimSpace(s.Text()), Position: i + 1}
+					switch specItem := item.(type) {
+					case *spec.TgTypeSpec:
+						specItem.SetProvenance(provenance)
+					case *spec.TgMethodSpec:
+						specItem.SetProvenance(provenance)
+					}
+					if item == nil && category != "recent-changes" && strings.Contains(anchorName, "-") {
+						h.report(ch, newProblem(CodeSkippedHeading, spec.SeverityInfo, s, "heading '%s' with anchor '%s' skipped", strings.TrimSpace(s.Text()), anchorName))
 					}
 				}
 			default:
@@ -51,32 +72,22 @@ func scrape(doc *goquery.Document, h helper) chan interface{} {
 				case *tgVersionSpec:
 					fillTgVersionSpec(specItem, nodeName, s)
 				case *spec.TgTypeSpec:
-					err := fillTgTypeSpec(specItem, nodeName, s, ch, h)
-					if err != nil {
-						ch <- err
-						return false
-					}
+					return fillTgTypeSpec(specItem, nodeName, s, ch, h)
 				case *spec.TgMethodSpec:
-					err := fillTgMethodSpec(specItem, nodeName, s, h)
-					if err != nil {
-						ch <- err
-						return false
-					}
+					return fillTgMethodSpec(specItem, nodeName, s, ch, h)
 				}
 			}
 
 			return true
 		})
 
-		if item != nil {
-			ch <- item
-		}
+		flush()
 	}()
 
 	return ch
 }
 
-func newSpecItem(category, anchorName, name, link string) (interface{}, error) {
+func newSpecItem(category, anchorName, name, link string, position int) (interface{}, error) {
 	if category == "recent-changes" {
 		link = hrefToLink(link, url_changelog)
 
@@ -89,9 +100,21 @@ func newSpecItem(category, anchorName, name, link string) (interface{}, error) {
 		link = hrefToLink(link, url_api_doc)
 
 		if name[0] == strings.ToUpper(name)[0] {
-			return spec.NewTgTypeSpec(category, name, link)
+			item, err := spec.NewTgTypeSpec(category, name, link)
+			if err != nil {
+				return nil, err
+			}
+			item.SetOrder(position)
+
+			return item, nil
 		} else {
-			return spec.NewTgMethodSpec(category, name, link)
+			item, err := spec.NewTgMethodSpec(category, name, link)
+			if err != nil {
+				return nil, err
+			}
+			item.SetOrder(position)
+
+			return item, nil
 		}
 	}
 
@@ -119,8 +142,9 @@ func fillTgVersionSpec(item *tgVersionSpec, nodeName string, s *goquery.Selectio
 	}
 }
 
-func fillTgTypeSpec(item *spec.TgTypeSpec, nodeName string, s *goquery.Selection, ch chan interface{}, h helper) error {
-	var err error
+// fillTgTypeSpec returns false if scraping must be stopped. Rows with problems are skipped by the lenient scraper.
+func fillTgTypeSpec(item *spec.TgTypeSpec, nodeName string, s *goquery.Selection, ch chan interface{}, h helper) bool {
+	keep := true
 	switch nodeName {
 	case "p":
 		item.SetDescription(concatDescription(item.GetDescription(), prepareDescription(s)))
@@ -137,29 +161,31 @@ func fillTgTypeSpec(item *spec.TgTypeSpec, nodeName string, s *goquery.Selection
 	case "table":
 		s.Find("tbody > tr").EachWithBreak(func(row int, tr *goquery.Selection) bool {
 			var property *spec.TgTypeSpecProperty
+			var problem *Problem
 			tr.Find("td").EachWithBreak(func(column int, td *goquery.Selection) bool {
 				switch column {
 				case 0:
+					var err error
 					property, err = spec.NewTgTypeSpecProperty(td.Text())
 					if err != nil {
-						err = errors.New("can't create new TgTypeSpecProperty, error: " + err.Error())
+						problem = newProblem(CodeInvalidRow, spec.SeverityError, td, "can't create new TgTypeSpecProperty, error: %s", err.Error())
 						return false
 					}
+					property.SetOrder(row + 1)
+					property.SetProvenance(rowProvenance(item.GetProvenance(), tr))
 				case 1:
 					text := td.Text()
 					if strings.Contains(text, "Integer") && strings.Contains(td.Next().Text(), "64-bit integer") {
 						text = strings.ReplaceAll(text, "Integer", "Integer64")
 					}
 
-					types := extractTypes(text, h)
-					if len(types) == 0 {
-						err = errors.New(fmt.Sprintf("scraping error: can't parse data type for property '%s' of object '%s'", property.GetName(), item.GetName()))
+					dataType := extractTypes(text, h)
+					if dataType == nil {
+						problem = newProblem(CodeUnparsedDataType, spec.SeverityError, td, "scraping error: can't parse data type for property '%s' of object '%s'", property.GetName(), item.GetName())
 
 						return false
 					}
-					for _, t := range types {
-						property.AddDataType(t)
-					}
+					property.AddDataType(dataType)
 				case 2:
 					property.SetOptional(strings.HasPrefix(td.Text(), "Optional."))
 
@@ -168,9 +194,14 @@ func fillTgTypeSpec(item *spec.TgTypeSpec, nodeName string, s *goquery.Selection
 						property.SetPredefinedValue(extractPredefinedValue(html))
 					}
 
+					if property.GetPredefinedValue() == nil {
+						property.SetEnum(extractEnum(td.Text(), property.GetDataTypes()))
+					}
+					property.SetConstraints(extractConstraints(td.Text()))
+
 					property.SetDescription(concatDescription(property.GetDescription(), prepareDescription(td)))
 				default:
-					err = errors.New(fmt.Sprintf("scraping error: can't parse properties of object '%s', too many columns", item.GetName()))
+					problem = newProblem(CodeUnknownColumns, spec.SeverityError, td, "scraping error: can't parse properties of object '%s', too many columns", item.GetName())
 
 					return false
 				}
@@ -178,12 +209,13 @@ func fillTgTypeSpec(item *spec.TgTypeSpec, nodeName string, s *goquery.Selection
 				return true
 			})
 
-			if err != nil {
-				return false
-			} else if property == nil {
-				err = errors.New(fmt.Sprintf("scraping error: expecting property for object %s, but it's missed", item.GetName()))
+			if problem == nil && property == nil {
+				problem = newProblem(CodeInvalidRow, spec.SeverityError, tr, "scraping error: expecting property for object %s, but it's missed", item.GetName())
+			}
+			if problem != nil {
+				keep = h.report(ch, problem)
 
-				return false
+				return keep
 			}
 
 			item.AddProperty(property)
@@ -192,52 +224,64 @@ func fillTgTypeSpec(item *spec.TgTypeSpec, nodeName string, s *goquery.Selection
 		})
 	}
 
-	return err
+	return keep
 }
 
-func fillTgMethodSpec(item *spec.TgMethodSpec, nodeName string, s *goquery.Selection, h helper) error {
-	var err error
+// fillTgMethodSpec returns false if scraping must be stopped. Rows with problems are skipped by the lenient scraper.
+func fillTgMethodSpec(item *spec.TgMethodSpec, nodeName string, s *goquery.Selection, ch chan interface{}, h helper) bool {
+	keep := true
 	switch nodeName {
 	case "p":
 		if item.GetDescription() == "" {
-			for _, returnType := range extractReturnTypes(s.Text(), h) {
+			returnType, conditionalReturns := extractReturnTypes(s.Text(), h)
+			if returnType != nil {
 				item.AddReturnType(returnType)
 			}
+			for _, conditionalReturn := range conditionalReturns {
+				item.AddConditionalReturn(conditionalReturn)
+			}
 		}
 
 		item.SetDescription(concatDescription(item.GetDescription(), prepareDescription(s)))
 	case "table":
+		relations := newArgumentRelations()
 		s.Find("tbody > tr").EachWithBreak(func(row int, tr *goquery.Selection) bool {
 			var argument *spec.TgMethodSpecArgument
+			var problem *Problem
 			tr.Find("td").EachWithBreak(func(column int, td *goquery.Selection) bool {
 				switch column {
 				case 0:
+					var err error
 					argument, err = spec.NewTgMethodSpecArgument(td.Text())
 					if err != nil {
-						err = errors.New("can't create new TgMethodSpecArgument, error: " + err.Error())
+						problem = newProblem(CodeInvalidRow, spec.SeverityError, td, "can't create new TgMethodSpecArgument, error: %s", err.Error())
 						return false
 					}
+					argument.SetOrder(row + 1)
+					argument.SetProvenance(rowProvenance(item.GetProvenance(), tr))
 				case 1:
 					text := td.Text()
 					if strings.Contains(text, "Integer") && strings.Contains(td.Next().Next().Text(), "64-bit integer") {
 						text = strings.ReplaceAll(text, "Integer", "Integer64")
 					}
 
-					types := extractTypes(text, h)
-					if len(types) == 0 {
-						err = errors.New(fmt.Sprintf("scraping error: can't parse data type for argument '%s' of method '%s'", argument.GetName(), item.GetName()))
+					dataType := extractTypes(text, h)
+					if dataType == nil {
+						problem = newProblem(CodeUnparsedDataType, spec.SeverityError, td, "scraping error: can't parse data type for argument '%s' of method '%s'", argument.GetName(), item.GetName())
 
 						return false
 					}
-					for _, t := range types {
-						argument.AddDataType(t)
-					}
+					argument.AddDataType(dataType)
 				case 2:
 					argument.SetRequired(td.Text() == "Yes")
 				case 3:
+					argument.SetEnum(extractEnum(td.Text(), argument.GetDataTypes()))
+					argument.SetConstraints(extractConstraints(td.Text()))
+					argument.SetDefault(extractDefault(td.Text(), argument.GetDataTypes()))
+					relations.collect(argument.GetName(), td.Text())
 					argument.SetDescription(concatDescription(argument.GetDescription(), prepareDescription(td)))
 				default:
-					err = errors.New(fmt.Sprintf("scraping error: can't parse arguments of method '%s', too many columns", item.GetName()))
+					problem = newProblem(CodeUnknownColumns, spec.SeverityError, td, "scraping error: can't parse arguments of method '%s', too many columns", item.GetName())
 
 					return false
 				}
@@ -245,21 +289,42 @@ func fillTgMethodSpec(item *spec.TgMethodSpec, nodeName string, s *goquery.Selec
 				return true
 			})
 
-			if err != nil {
-				return false
-			} else if argument == nil {
-				err = errors.New(fmt.Sprintf("scraping error: expecting argument for method %s, but it's missed", item.GetName()))
+			if problem == nil && argument == nil {
+				problem = newProblem(CodeInvalidRow, spec.SeverityError, tr, "scraping error: expecting argument for method %s, but it's missed", item.GetName())
+			}
+			if problem != nil {
+				keep = h.report(ch, problem)
 
-				return false
+				return keep
 			}
 
 			item.AddArgument(argument)
 
 			return true
 		})
+
+		if keep {
+			relations.addArgumentGroups(item)
+			resolveReturnConditions(item)
+		}
 	}
 
-	return err
+	return keep
+}
+
+// rowProvenance returns the provenance of the table row of the element which has the provenance of its heading.
+func rowProvenance(owner *spec.Provenance, tr *goquery.Selection) *spec.Provenance {
+	provenance := &spec.Provenance{Position: tr.Closest("table").Index() + 1, Row: tr.Index() + 1}
+	if owner != nil {
+		provenance.Anchor, provenance.Heading = owner.Anchor, owner.Heading
+	}
+
+	tr.Find("td").Each(func(i int, td *goquery.Selection) {
+		html, _ := td.Html()
+		provenance.Cells = append(provenance.Cells, strings.TrimSpace(html))
+	})
+
+	return provenance
 }
 
 func extractPredefinedValue(html string) *spec.TgTypeSpecPropertyValue {
@@ -273,22 +338,20 @@ func extractPredefinedValue(html string) *spec.TgTypeSpecPropertyValue {
 	return nil
 }
 
-func extractReturnTypes(text string, h helper) []spec.DataTypeDefinition {
-	var types []spec.DataTypeDefinition
-
+func extractReturnTypes(text string, h helper) (spec.DataTypeDefinition, []*spec.TgMethodSpecConditionalReturn) {
 	matches := regexp.MustCompile(`(?i)(?:on success,|returns)([^.]*)(?:on success)?`).FindStringSubmatch(text)
 	if len(matches) < 2 {
 		matches = regexp.MustCompile(`(?i)(?:An)([^.]*)(?:is returned)`).FindStringSubmatch(text)
 	}
 
 	if len(matches) < 2 {
-		return types
+		return nil, nil
 	}
 
 	matches[1] = strings.ReplaceAll(matches[1], "Array", "array")
 	typesList := regexp.MustCompile(`\b[A-Z].*?\b`).FindAllString(matches[1], -1)
 	if len(typesList) == 0 {
-		return types
+		return nil, nil
 	}
 
 	join, prefix := " or ", ""
@@ -296,22 +359,43 @@ func extractReturnTypes(text string, h helper) []spec.DataTypeDefinition {
 		join, prefix = ", ", "Array of "
 	}
 
-	return extractTypes(prefix+strings.Join(typesList, join), h)
+	return extractTypes(prefix+strings.Join(typesList, join), h), extractConditionalReturns(text, h)
 }
 
-func extractTypes(text string, h helper) []spec.DataTypeDefinition {
-	oneOfTypes := strings.Split(text, " or ")
+// extractConditionalReturns extracts conditions from phrases like
+// `if the edited message is not an inline message, the edited Message is returned, otherwise True is returned`.
+func extractConditionalReturns(text string, h helper) []*spec.TgMethodSpecConditionalReturn {
+	matches := regexp.MustCompile(`(?i)\bif ([^,]+), (?:the )?(?:[a-z]+ )*?([A-Z]\w*) is returned, otherwise ([A-Z]\w*) is returned`).FindStringSubmatch(text)
+	if len(matches) != 4 {
+		return nil
+	}
+
+	var conditionalReturns []*spec.TgMethodSpecConditionalReturn
+	for _, pair := range [][2]string{{matches[2], matches[1]}, {matches[3], "otherwise"}} {
+		conditionalReturn, err := spec.NewTgMethodSpecConditionalReturn(h.declareDataType(fixTypeDef(pair[0])), pair[1])
+		if err == nil {
+			conditionalReturns = append(conditionalReturns, conditionalReturn)
+		}
+	}
 
-	var types []spec.DataTypeDefinition
+	return conditionalReturns
+}
 
-	for _, unparsedType := range oneOfTypes {
-		typeDef := extractTypeDef(unparsedType)
-		if typeDef != "" {
-			types = append(types, h.declareDataType(typeDef))
+// extractTypes returns the data type described by the text (a union for texts like `Integer or String`)
+// or nil if the text doesn't describe any data type.
+func extractTypes(text string, h helper) spec.DataTypeDefinition {
+	var typeDefs []string
+	for _, unparsedType := range strings.Split(text, " or ") {
+		if typeDef := extractTypeDef(unparsedType); typeDef != "" {
+			typeDefs = append(typeDefs, typeDef)
 		}
 	}
 
-	return types
+	if len(typeDefs) == 0 {
+		return nil
+	}
+
+	return h.declareDataType(strings.Join(typeDefs, "|"))
 }
 
 func extractTypeDef(text string) string {
@@ -322,14 +406,10 @@ func extractTypeDef(text string) string {
 		def = "array"
 		text = strings.TrimPrefix(text, "Array of ")
 		itemsDef := extractTypeDef(text)
-		if strings.HasPrefix(itemsDef, "_anyOf") {
-			itemsDef = strings.ReplaceAll(itemsDef, "_anyOf", "")
-		}
 		if itemsDef != "" {
 			def += "<" + itemsDef + ">"
 		}
 	} else if strings.Contains(text, ", ") {
-		def = "_anyOf"
 		anyOfTypes := strings.Split(text, ",")
 		var extractedAnyOfTypes []string
 		for _, unparsedType := range anyOfTypes {
@@ -338,7 +418,7 @@ func extractTypeDef(text string) string {
 				extractedAnyOfTypes = append(extractedAnyOfTypes, typeDef)
 			}
 		}
-		def += strings.Join(extractedAnyOfTypes, "|")
+		def = strings.Join(extractedAnyOfTypes, "|")
 
 	} else {
 		def = fixTypeDef(strings.TrimSpace(text))
@@ -349,7 +429,9 @@ func extractTypeDef(text string) string {
 
 func fixTypeDef(typeDef string) string {
 	switch typeDef {
-	case "True", "False", "Bool", "Boolean":
+	case "True":
+		return "true"
+	case "False", "Bool", "Boolean":
 		return "boolean"
 	case "Float number", "Float":
 		return "float"
@@ -0,0 +1,52 @@
+package scrape
+
+import (
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+func TestExtractTypes(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Array of Array of PhotoSize", "array<array<PhotoSize>>"},
+		{"Array of InputMediaAudio, InputMediaDocument, InputMediaPhoto and InputMediaVideo", "array<InputMediaAudio|InputMediaDocument|InputMediaPhoto|InputMediaVideo>"},
+		{"Integer or String", "int32|string"},
+		{"String or Integer", "int32|string"},
+		{"InputFile or String", "InputFile|string"},
+		{"True", "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			spectest.New(t, func(as *spec.ApiSpec) error {
+				if got := extractTypes(tt.text, createHelper(as, false)); got == nil || got.GetDefinition() != tt.want {
+					t.Errorf("got %v, want %s", got, tt.want)
+				}
+
+				return nil
+			})
+		})
+	}
+}
+
+func TestExtractNestedArrays(t *testing.T) {
+	spectest.New(t, func(as *spec.ApiSpec) error {
+		outer, ok := extractTypes("Array of Array of PhotoSize", createHelper(as, false)).(*spec.ArrayDataType)
+		if !ok {
+			t.Fatalf("expected the array, got %#v", outer)
+		}
+		inner, ok := outer.GetElementDataType().(*spec.ArrayDataType)
+		if !ok {
+			t.Fatalf("expected the array of arrays, got %#v", outer.GetElementDataType())
+		}
+		if _, ok := inner.GetElementDataType().(*spec.ObjectDataType); !ok || inner.GetElementDataType().GetDefinition() != "PhotoSize" {
+			t.Errorf("expected arrays of PhotoSize, got %#v", inner.GetElementDataType())
+		}
+
+		return nil
+	})
+}
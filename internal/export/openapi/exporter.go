@@ -18,16 +18,22 @@ type OpenapiExporter struct {
 }
 
 func NewOpenapiExporter(as spec.ApiSpec) (*OpenapiExporter, error) {
+	return NewOrderedOpenapiExporter(as, spec.OrderAlphabetical)
+}
+
+// NewOrderedOpenapiExporter creates an exporter which lists required properties according to the provided ordering.
+// In case of the document ordering, properties are annotated with the `x-order` extension.
+func NewOrderedOpenapiExporter(as spec.ApiSpec, ordering spec.Ordering) (*OpenapiExporter, error) {
 	if err := as.SelfCheck(); err != nil {
 		return nil, errors.New("invalid spec: " + err.Error())
 	}
 
-	schemas, err := schemas(&as)
+	schemas, err := schemas(&as, ordering)
 	if err != nil {
 		return nil, err
 	}
 
-	paths, err := paths(&as)
+	paths, err := paths(&as, ordering)
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +44,7 @@ func NewOpenapiExporter(as spec.ApiSpec) (*OpenapiExporter, error) {
 	data["externalDocs"] = externalDocs()
 	data["servers"] = servers()
 	data["paths"] = paths
-	data["components"] = map[string]interface{}{"schemas": schemas, "responses": responses()}
+	data["components"] = map[string]interface{}{"schemas": schemas, "responses": responses(&as)}
 	data["security"] = []map[string]interface{}{{}}
 
 	return &OpenapiExporter{as, data}, nil
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/alserom/tg-bot-api-spec/internal/utils"
 	"github.com/alserom/tg-bot-api-spec/pkg/spec"
 )
 
@@ -71,7 +72,26 @@ func servers() []map[string]interface{} {
 	}
 }
 
-func responses() map[string]interface{} {
+// responses refers to ResponseParameters only if the spec has it, e.g. subsets of the spec can miss it.
+func responses(as *spec.ApiSpec) map[string]interface{} {
+	properties := map[string]interface{}{
+		"ok": map[string]interface{}{
+			"type":    "boolean",
+			"default": false,
+		},
+		"error_code": map[string]interface{}{
+			"type": "integer",
+		},
+		"description": map[string]interface{}{
+			"type": "string",
+		},
+	}
+	if _, exists := as.GetType("ResponseParameters"); exists {
+		properties["parameters"] = map[string]interface{}{
+			"$ref": refToSchema("ResponseParameters"),
+		}
+	}
+
 	return map[string]interface{}{
 		"error": map[string]interface{}{
 			"description": "Error",
@@ -83,21 +103,7 @@ func responses() map[string]interface{} {
 							"ok",
 							"error_code",
 						},
-						"properties": map[string]interface{}{
-							"ok": map[string]interface{}{
-								"type":    "boolean",
-								"default": false,
-							},
-							"error_code": map[string]interface{}{
-								"type": "integer",
-							},
-							"description": map[string]interface{}{
-								"type": "string",
-							},
-							"parameters": map[string]interface{}{
-								"$ref": refToSchema("ResponseParameters"),
-							},
-						},
+						"properties": properties,
 					},
 				},
 			},
@@ -105,7 +111,7 @@ func responses() map[string]interface{} {
 	}
 }
 
-func paths(as *spec.ApiSpec) (map[string]interface{}, error) {
+func paths(as *spec.ApiSpec, ordering spec.Ordering) (map[string]interface{}, error) {
 	paths := make(map[string]interface{})
 
 	for _, m := range as.GetMethods() {
@@ -120,11 +126,13 @@ func paths(as *spec.ApiSpec) (map[string]interface{}, error) {
 			},
 		}
 
+		setHistory(m.GetHistory(), operation)
+
 		var operationType string
 		if len(m.GetArguments()) > 0 {
 			operationType = "post"
 
-			content, err := getRequestBodyContent(m)
+			content, err := getRequestBodyContent(m, ordering)
 			if err != nil {
 				return nil, err
 			}
@@ -135,15 +143,11 @@ func paths(as *spec.ApiSpec) (map[string]interface{}, error) {
 		}
 
 		resultSchema := map[string]interface{}{}
-		if len(m.GetReturnTypes()) == 1 {
-			setPropertyType(m.GetReturnTypes()[0], resultSchema)
-		} else {
-			oneOf := make([]map[string]interface{}, len(m.GetReturnTypes()))
-			for i, dt := range m.GetReturnTypes() {
-				oneOf[i] = make(map[string]interface{})
-				setPropertyType(dt, oneOf[i])
+		setDataTypes(m.GetReturnTypes(), resultSchema)
+		if oneOf, ok := resultSchema["oneOf"].([]map[string]interface{}); ok {
+			for i, dt := range spec.FlattenDataTypes(m.GetReturnTypes()) {
+				setReturnCondition(m.GetConditionalReturns(), dt, oneOf[i])
 			}
-			resultSchema["oneOf"] = oneOf
 		}
 
 		operation["responses"] = map[string]interface{}{
@@ -176,7 +180,8 @@ func paths(as *spec.ApiSpec) (map[string]interface{}, error) {
 			"default": map[string]interface{}{"$ref": "#/components/responses/error"},
 		}
 
-		if m.GetName() == "setWebhook" {
+		// The callback refers to Update, so it's skipped if the spec misses the type, e.g. in subsets of the spec.
+		if _, exists := as.GetType("Update"); exists && m.GetName() == "setWebhook" {
 			operation["callbacks"] = map[string]interface{}{
 				"incomingUpdate": map[string]interface{}{
 					"{$request.body#/url}": map[string]interface{}{
@@ -226,7 +231,26 @@ func paths(as *spec.ApiSpec) (map[string]interface{}, error) {
 	return paths, nil
 }
 
-func getRequestBodyContent(m *spec.TgMethodSpec) (map[string]interface{}, error) {
+// setReturnCondition documents the variant of the result according to the condition under which it's returned.
+func setReturnCondition(conditionalReturns []*spec.TgMethodSpecConditionalReturn, dtDef spec.DataTypeDefinition, variant map[string]interface{}) {
+	for _, cr := range conditionalReturns {
+		if cr.GetDataType().GetDefinition() != dtDef.GetDefinition() {
+			continue
+		}
+
+		if cr.GetCondition() == "otherwise" {
+			variant["description"] = "Returned otherwise."
+		} else {
+			variant["description"] = "Returned if " + cr.GetCondition() + "."
+		}
+
+		if len(cr.GetArguments()) > 0 {
+			variant["x-depends-on"] = cr.GetArguments()
+		}
+	}
+}
+
+func getRequestBodyContent(m *spec.TgMethodSpec, ordering spec.Ordering) (map[string]interface{}, error) {
 	content := make(map[string]interface{})
 	needMultipart, argsWithInputFile := isInputFileInDataTypes(m.GetArguments())
 	var mediaType string
@@ -242,7 +266,9 @@ func getRequestBodyContent(m *spec.TgMethodSpec) (map[string]interface{}, error)
 	}
 	var required []string
 	props := make(map[string]interface{})
-	for _, a := range m.GetArguments() {
+	// argProps keeps properties of all the arguments, including the ones which have only InputFile and aren't in the JSON schema.
+	argProps := make(map[string]map[string]interface{})
+	for _, a := range m.GetOrderedArguments(ordering) {
 		if a.IsRequired() {
 			required = append(required, a.GetName())
 		}
@@ -250,22 +276,22 @@ func getRequestBodyContent(m *spec.TgMethodSpec) (map[string]interface{}, error)
 		prop := map[string]interface{}{
 			"description": a.GetDescription(),
 		}
+		setOrder(a.GetOrder(), ordering, prop)
+		setHistory(a.GetHistory(), prop)
+
+		if a.GetDefault() != nil {
+			prop["default"] = a.GetDefault()
+		}
 
 		dataTypes := a.GetDataTypes()
 		if len(argsWithInputFile) > 0 {
 			dataTypes = filterInputFileDataType(dataTypes)
 		}
 
-		if len(dataTypes) == 1 {
-			setPropertyType(dataTypes[0], prop)
-		} else {
-			oneOf := make([]map[string]interface{}, len(dataTypes))
-			for i, dt := range dataTypes {
-				oneOf[i] = make(map[string]interface{})
-				setPropertyType(dt, oneOf[i])
-			}
-			prop["oneOf"] = oneOf
-		}
+		setDataTypes(dataTypes, prop)
+		setEnum(a.GetEnum(), prop)
+		setConstraints(a.GetConstraints(), prop)
+		argProps[a.GetName()] = prop
 
 		if len(dataTypes) > 0 {
 			props[a.GetName()] = prop
@@ -278,6 +304,8 @@ func getRequestBodyContent(m *spec.TgMethodSpec) (map[string]interface{}, error)
 
 	schema["properties"] = props
 
+	setArgumentGroups(m.GetArgumentGroups(), schema)
+
 	if len(argsWithInputFile) > 0 {
 		content["application/json"] = map[string]interface{}{"schema": schema}
 		schema2 := make(map[string]interface{})
@@ -289,8 +317,20 @@ func getRequestBodyContent(m *spec.TgMethodSpec) (map[string]interface{}, error)
 			props2[k] = v
 		}
 
+		// The InputFile schema replaces data types of the argument, other keywords (order, history, etc.) are kept.
 		for argName, propData := range argsWithInputFile {
-			props2[argName] = propData
+			prop := make(map[string]interface{})
+			for k, v := range argProps[argName] {
+				switch k {
+				case "type", "format", "items", "oneOf", "const", "$ref":
+				default:
+					prop[k] = v
+				}
+			}
+			for k, v := range propData.(map[string]interface{}) {
+				prop[k] = v
+			}
+			props2[argName] = prop
 		}
 		schema2["properties"] = props2
 		content["multipart/form-data"] = map[string]interface{}{"schema": schema2}
@@ -301,18 +341,77 @@ func getRequestBodyContent(m *spec.TgMethodSpec) (map[string]interface{}, error)
 	return content, nil
 }
 
+// setArgumentGroups adds groups to the request schema as `allOf` clauses. Several arguments of the RequiredIfAbsent group,
+// which are required together instead of other ones (e.g. `chat_id` and `message_id` instead of `inline_message_id`),
+// are also described by `dependentRequired`, as passing one of them requires the rest. The arguments the group depends on
+// aren't bound this way, as the group doesn't require them.
+func setArgumentGroups(groups []*spec.TgMethodSpecArgumentGroup, schema map[string]interface{}) {
+	var schemas []map[string]interface{}
+	dependentRequired := make(map[string][]string)
+	for _, g := range groups {
+		switch g.GetKind() {
+		case spec.ExactlyOneOf:
+			oneOf := make([]map[string]interface{}, len(g.GetArguments()))
+			for i, name := range g.GetArguments() {
+				oneOf[i] = map[string]interface{}{"required": []string{name}}
+			}
+			schemas = append(schemas, map[string]interface{}{"oneOf": oneOf})
+		case spec.RequiredIfAbsent:
+			schemas = append(schemas, map[string]interface{}{
+				"anyOf": []map[string]interface{}{
+					{"required": g.GetDependsOn()},
+					{"required": g.GetArguments()},
+				},
+			})
+			addDependentRequired(dependentRequired, g.GetArguments())
+		case spec.MutuallyExclusive:
+			var pairs []map[string]interface{}
+			args := g.GetArguments()
+			for i := 0; i < len(args); i++ {
+				for j := i + 1; j < len(args); j++ {
+					pairs = append(pairs, map[string]interface{}{"required": []string{args[i], args[j]}})
+				}
+			}
+			schemas = append(schemas, map[string]interface{}{"not": map[string]interface{}{"anyOf": pairs}})
+		}
+	}
+
+	if len(schemas) > 0 {
+		schema["allOf"] = schemas
+	}
+	if len(dependentRequired) > 0 {
+		schema["dependentRequired"] = dependentRequired
+	}
+}
+
+// addDependentRequired makes every argument of the list require the other ones.
+func addDependentRequired(dependentRequired map[string][]string, arguments []string) {
+	if len(arguments) < 2 {
+		return
+	}
+
+	for _, name := range arguments {
+		for _, other := range arguments {
+			if other != name && !utils.Contains(dependentRequired[name], other) {
+				dependentRequired[name] = append(dependentRequired[name], other)
+			}
+		}
+	}
+}
+
 func isInputFileInDataTypes(args []*spec.TgMethodSpecArgument) (bool, map[string]interface{}) {
 	argNames := make(map[string]interface{})
 	for _, a := range args {
-		if len(a.GetDataTypes()) == 1 && a.GetDataTypes()[0].GetDefinition() == "InputFile" && a.IsRequired() {
+		dataTypes := spec.FlattenDataTypes(a.GetDataTypes())
+		if len(dataTypes) == 1 && dataTypes[0].GetDefinition() == "InputFile" && a.IsRequired() {
 			return true, make(map[string]interface{})
 		}
 
-		for _, dt := range a.GetDataTypes() {
+		for _, dt := range dataTypes {
 			if dt.GetDefinition() == "InputFile" {
 				argNames[a.GetName()] = map[string]interface{}{
 					"description": a.GetDescription(),
-					"$ref":        refToSchema("InputFile"),
+					"$ref":        refToSchema(inputFileSchemaName(a.GetDataTypes())),
 				}
 			}
 		}
@@ -321,18 +420,33 @@ func isInputFileInDataTypes(args []*spec.TgMethodSpecArgument) (bool, map[string
 	return len(argNames) > 0, argNames
 }
 
+// inputFileSchemaName returns the name of the alias of the data types with InputFile (e.g. `FileInput`) if it's declared.
+func inputFileSchemaName(dataTypes []spec.DataTypeDefinition) string {
+	if len(dataTypes) == 1 {
+		if union, ok := dataTypes[0].(*spec.UnionDataType); ok && union.GetAlias() != nil {
+			return union.GetAlias().GetName()
+		}
+	}
+
+	return "InputFile"
+}
+
 func filterInputFileDataType(dataTypes []spec.DataTypeDefinition) []spec.DataTypeDefinition {
 	var filtered []spec.DataTypeDefinition
-	for _, dt := range dataTypes {
+	for _, dt := range spec.FlattenDataTypes(dataTypes) {
 		if dt.GetDefinition() != "InputFile" {
 			filtered = append(filtered, dt)
 		}
 	}
 
+	if len(filtered) == len(spec.FlattenDataTypes(dataTypes)) {
+		return dataTypes
+	}
+
 	return filtered
 }
 
-func schemas(as *spec.ApiSpec) (map[string]interface{}, error) {
+func schemas(as *spec.ApiSpec, ordering spec.Ordering) (map[string]interface{}, error) {
 	schemas := make(map[string]interface{})
 
 	for _, t := range as.GetTypes() {
@@ -346,6 +460,8 @@ func schemas(as *spec.ApiSpec) (map[string]interface{}, error) {
 			},
 		}
 
+		setHistory(t.GetHistory(), obj)
+
 		if t.GetName() == "InputFile" {
 			obj["type"] = "string"
 			obj["format"] = "binary"
@@ -356,27 +472,21 @@ func schemas(as *spec.ApiSpec) (map[string]interface{}, error) {
 
 		if len(t.GetChildren()) > 0 {
 			oneOf := make([]map[string]string, len(t.GetChildren()))
-			discriminatorMapping := make(map[string]string)
-			discriminatorPropName := ""
-			dpnCheck := make(map[string]bool)
 			for i, child := range t.GetChildren() {
 				oneOf[i] = map[string]string{"$ref": refToSchema(child.GetName())}
-				for _, p := range child.GetProperties() {
-					if p.GetPredefinedValue() != nil {
-						discriminatorMapping[string(*p.GetPredefinedValue())] = refToSchema(child.GetName())
-						discriminatorPropName = p.GetName()
-						dpnCheck[p.GetName()] = true
-						break
-					}
-				}
 			}
 
 			obj["oneOf"] = oneOf
 
-			if len(oneOf) == len(discriminatorMapping) && len(dpnCheck) == 1 {
+			if discriminator := t.GetDiscriminator(); discriminator != nil {
+				mapping := make(map[string]string)
+				for value, child := range discriminator.GetMapping() {
+					mapping[value] = refToSchema(child.GetName())
+				}
+
 				obj["discriminator"] = map[string]interface{}{
-					"propertyName": discriminatorPropName,
-					"mapping":      discriminatorMapping,
+					"propertyName": discriminator.GetPropertyName(),
+					"mapping":      mapping,
 				}
 			}
 		}
@@ -384,10 +494,12 @@ func schemas(as *spec.ApiSpec) (map[string]interface{}, error) {
 		if len(t.GetProperties()) > 0 {
 			var required []string
 			props := make(map[string]interface{})
-			for _, p := range t.GetProperties() {
+			for _, p := range t.GetOrderedProperties(ordering) {
 				prop := map[string]interface{}{
 					"description": p.GetDescription(),
 				}
+				setOrder(p.GetOrder(), ordering, prop)
+				setHistory(p.GetHistory(), prop)
 
 				if p.GetPredefinedValue() != nil {
 					prop["default"] = string(*p.GetPredefinedValue())
@@ -397,16 +509,9 @@ func schemas(as *spec.ApiSpec) (map[string]interface{}, error) {
 					required = append(required, p.GetName())
 				}
 
-				if len(p.GetDataTypes()) == 1 {
-					setPropertyType(p.GetDataTypes()[0], prop)
-				} else {
-					oneOf := make([]map[string]interface{}, len(p.GetDataTypes()))
-					for i, dt := range p.GetDataTypes() {
-						oneOf[i] = make(map[string]interface{})
-						setPropertyType(dt, oneOf[i])
-					}
-					prop["oneOf"] = oneOf
-				}
+				setDataTypes(p.GetDataTypes(), prop)
+				setEnum(p.GetEnum(), prop)
+				setConstraints(p.GetConstraints(), prop)
 
 				props[p.GetName()] = prop
 			}
@@ -421,13 +526,97 @@ func schemas(as *spec.ApiSpec) (map[string]interface{}, error) {
 		schemas[t.GetName()] = obj
 	}
 
+	for _, a := range as.GetAliases() {
+		alias := map[string]interface{}{}
+		setUnionType(a.GetDataType().GetMemberDataTypes(), alias)
+		schemas[a.GetName()] = alias
+	}
+
 	return schemas, nil
 }
 
+func setOrder(order int, ordering spec.Ordering, prop map[string]interface{}) {
+	if ordering == spec.OrderDocument && order > 0 {
+		prop["x-order"] = order
+	}
+}
+
+// setHistory adds versions of the element, so generators can guard it for bots which use older versions of Bot API.
+func setHistory(history *spec.VersionHistory, obj map[string]interface{}) {
+	if history == nil {
+		return
+	}
+
+	if history.Since != "" {
+		obj["x-since"] = history.Since
+	}
+	if history.Until != "" {
+		obj["x-until"] = history.Until
+	}
+}
+
+// setEnum adds the list of allowed values to the string property or to the items of the string array property.
+func setEnum(values []string, prop map[string]interface{}) {
+	if len(values) == 0 {
+		return
+	}
+
+	switch prop["type"] {
+	case "string":
+		prop["enum"] = values
+	case "array":
+		if items, ok := prop["items"].(map[string]interface{}); ok && items["type"] == "string" {
+			items["enum"] = values
+		}
+	}
+}
+
+// setConstraints adds length limits to the string property and value limits to the numeric property.
+func setConstraints(c *spec.ValueConstraints, prop map[string]interface{}) {
+	if c == nil {
+		return
+	}
+
+	switch prop["type"] {
+	case "string":
+		if c.MinLength != nil {
+			prop["minLength"] = *c.MinLength
+		}
+		if c.MaxLength != nil {
+			prop["maxLength"] = *c.MaxLength
+		}
+	case "integer", "number":
+		if c.Minimum != nil {
+			prop["minimum"] = *c.Minimum
+		}
+		if c.Maximum != nil {
+			prop["maximum"] = *c.Maximum
+		}
+	}
+}
+
 func refToSchema(name string) string {
 	return "#/components/schemas/" + name
 }
 
+// setDataTypes describes the property which can be any of the provided data types.
+func setDataTypes(dataTypes []spec.DataTypeDefinition, prop map[string]interface{}) {
+	if len(dataTypes) == 1 {
+		setPropertyType(dataTypes[0], prop)
+	} else {
+		setUnionType(spec.FlattenDataTypes(dataTypes), prop)
+	}
+}
+
+func setUnionType(members []spec.DataTypeDefinition, prop map[string]interface{}) {
+	oneOf := make([]map[string]interface{}, len(members))
+	for i, member := range members {
+		oneOf[i] = make(map[string]interface{})
+		setPropertyType(member, oneOf[i])
+	}
+	prop["oneOf"] = oneOf
+}
+
 func setPropertyType(dtDef spec.DataTypeDefinition, prop map[string]interface{}) {
 	switch dt := dtDef.(type) {
 	case *spec.ObjectDataType:
@@ -435,24 +624,26 @@ func setPropertyType(dtDef spec.DataTypeDefinition, prop map[string]interface{})
 	case *spec.ArrayDataType:
 		prop["type"] = "array"
 		items := make(map[string]interface{})
-		if len(dt.GetElementDataTypes()) == 1 {
-			setPropertyType(dt.GetElementDataTypes()[0], items)
-		} else {
-			oneOf := make([]map[string]interface{}, len(dt.GetElementDataTypes()))
-			for i, ai := range dt.GetElementDataTypes() {
-				oneOf[i] = make(map[string]interface{})
-				setPropertyType(ai, oneOf[i])
-			}
-			items["oneOf"] = oneOf
+		if dt.GetElementDataType() != nil {
+			setPropertyType(dt.GetElementDataType(), items)
 		}
 
 		prop["items"] = items
+	case *spec.UnionDataType:
+		if dt.GetAlias() != nil {
+			prop["$ref"] = refToSchema(dt.GetAlias().GetName())
+		} else {
+			setUnionType(dt.GetMemberDataTypes(), prop)
+		}
 	case *spec.ScalarDataType:
 		dtTitle, dtFormat := scalarTypeFromDefinition(*dt)
 		prop["type"] = dtTitle
 		if dtFormat != "" {
 			prop["format"] = dtFormat
 		}
+		if dt.IsLiteral() {
+			prop["const"] = true
+		}
 	}
 }
 
@@ -464,6 +655,8 @@ func scalarTypeFromDefinition(dt spec.ScalarDataType) (string, string) {
 		return "integer", "int32"
 	case "int64":
 		return "integer", "int64"
+	case "true":
+		return "boolean", ""
 	}
 
 	return strings.ToLower(dt.GetDefinition()), ""
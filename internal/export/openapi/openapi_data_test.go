@@ -0,0 +1,133 @@
+package export_to_openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/internal/datasource/scrape"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+func TestArgumentGroups(t *testing.T) {
+	var method *spec.TgMethodSpec
+	spectest.New(t, func(as *spec.ApiSpec) error {
+		method = spectest.AddMethod(as, "editMessageText", "Message|true",
+			"chat_id int64|string", "message_id int32", "inline_message_id string", "text string", "parse_mode string", "entities array<string>")
+
+		for _, group := range []struct {
+			kind                 spec.ArgumentGroupKind
+			arguments, dependsOn []string
+		}{
+			{spec.RequiredIfAbsent, []string{"chat_id", "message_id"}, []string{"inline_message_id"}},
+			{spec.RequiredIfAbsent, []string{"inline_message_id"}, []string{"chat_id", "message_id"}},
+			{spec.MutuallyExclusive, []string{"entities", "parse_mode"}, nil},
+		} {
+			g, err := spec.NewTgMethodSpecArgumentGroup(group.kind, group.arguments, group.dependsOn)
+			if err != nil {
+				return err
+			}
+			method.AddArgumentGroup(g)
+		}
+
+		return nil
+	})
+
+	content, err := getRequestBodyContent(method, spec.OrderAlphabetical)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := content["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+
+	tests := []struct {
+		keyword string
+		want    string
+	}{
+		{"allOf", `[` +
+			`{"anyOf":[{"required":["inline_message_id"]},{"required":["chat_id","message_id"]}]},` +
+			`{"anyOf":[{"required":["chat_id","message_id"]},{"required":["inline_message_id"]}]},` +
+			`{"not":{"anyOf":[{"required":["entities","parse_mode"]}]}}` +
+			`]`},
+		{"dependentRequired", `{"chat_id":["message_id"],"message_id":["chat_id"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.keyword, func(t *testing.T) {
+			got, err := json.Marshal(schema[tt.keyword])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrapedArgumentGroups(t *testing.T) {
+	s, err := scrape.NewFileScraper("testdata/editmessagetext.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	as, err := spec.NewApiSpec(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	method, exists := as.GetMethod("editMessageText")
+	if !exists {
+		t.Fatal("editMessageText isn't scraped")
+	}
+
+	content, err := getRequestBodyContent(method, spec.OrderAlphabetical)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := content["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+
+	got, err := json.Marshal(schema["dependentRequired"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"chat_id":["message_id"],"message_id":["chat_id"]}`; string(got) != want {
+		t.Errorf("got dependentRequired %s, want %s", got, want)
+	}
+}
+
+func TestLiteralTrue(t *testing.T) {
+	as := spectest.New(t, func(as *spec.ApiSpec) error {
+		spectest.AddType(as, "Message", "message_id int32")
+		spectest.AddType(as, "ChatMemberRestricted", "is_member true", "can_send_messages boolean", "result Message|true")
+
+		return nil
+	})
+
+	schemas, err := schemas(as, spec.OrderAlphabetical)
+	if err != nil {
+		t.Fatal(err)
+	}
+	properties := schemas["ChatMemberRestricted"].(map[string]interface{})["properties"].(map[string]interface{})
+
+	tests := []struct {
+		property string
+		want     string
+	}{
+		{"is_member", `{"const":true,"type":"boolean"}`},
+		{"can_send_messages", `{"type":"boolean"}`},
+		{"result", `{"oneOf":[{"$ref":"#/components/schemas/Message"},{"const":true,"type":"boolean"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.property, func(t *testing.T) {
+			prop := properties[tt.property].(map[string]interface{})
+			delete(prop, "description")
+
+			got, err := json.Marshal(prop)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
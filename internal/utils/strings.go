@@ -0,0 +1,12 @@
+package utils
+
+// Contains reports whether the value is in the list.
+func Contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
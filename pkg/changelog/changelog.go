@@ -0,0 +1,234 @@
+package changelog
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+// ApiDocLink is the page which anchors of types and methods belong to.
+const ApiDocLink = "https://core.telegram.org/bots/api"
+
+type DataSource interface {
+	FillChangelog(cl *Changelog) error
+}
+
+// Changelog is the list of Bot API releases as they're listed in the official changelog, the latest release goes first.
+type Changelog struct {
+	releases []*Release
+	r_mu     *sync.RWMutex
+}
+
+func (cl *Changelog) AddRelease(release *Release) error {
+	if release == nil {
+		return errors.New("skipped adding a nil pointer to list")
+	}
+
+	cl.r_mu.Lock()
+	cl.releases = append(cl.releases, release)
+	cl.r_mu.Unlock()
+
+	return nil
+}
+
+func (cl Changelog) GetReleases() []*Release {
+	return cl.releases
+}
+
+func (cl Changelog) GetRelease(version string) (*Release, bool) {
+	for _, r := range cl.releases {
+		if r.version == version {
+			return r, true
+		}
+	}
+
+	return nil, false
+}
+
+// ResolveReferences links references to anchors of the API doc with types and methods of the spec.
+// It returns references which point to the API doc, but don't match any element of the spec.
+func (cl *Changelog) ResolveReferences(as *spec.ApiSpec) []*Reference {
+	elements := make(map[string]*Reference)
+	for _, t := range as.GetTypes() {
+		elements[strings.ToLower(anchorOf(t.GetLink()))] = &Reference{kind: ReferenceType, name: t.GetName()}
+	}
+	for _, m := range as.GetMethods() {
+		elements[strings.ToLower(anchorOf(m.GetLink()))] = &Reference{kind: ReferenceMethod, name: m.GetName()}
+	}
+
+	var unresolved []*Reference
+	var resolve func(entries []*Entry)
+	resolve = func(entries []*Entry) {
+		for _, e := range entries {
+			for _, r := range e.references {
+				anchor := r.GetAnchor()
+				if anchor == "" {
+					continue
+				}
+
+				if element, exists := elements[strings.ToLower(anchor)]; exists {
+					r.kind, r.name = element.kind, element.name
+				} else {
+					unresolved = append(unresolved, r)
+				}
+			}
+			resolve(e.entries)
+		}
+	}
+
+	for _, release := range cl.releases {
+		resolve(release.entries)
+	}
+
+	return unresolved
+}
+
+func NewChangelog(ds DataSource) (*Changelog, error) {
+	cl := &Changelog{r_mu: &sync.RWMutex{}}
+
+	if err := ds.FillChangelog(cl); err != nil {
+		return nil, err
+	}
+
+	return cl, nil
+}
+
+type Release struct {
+	version string
+	date    string
+	link    string
+	entries []*Entry
+}
+
+// SetVersion sets the Bot API version of the release. Early releases of the changelog have no version.
+func (r *Release) SetVersion(version string) {
+	r.version = version
+}
+
+func (r Release) GetVersion() string {
+	return r.version
+}
+
+// GetDate returns the date as it's written in the changelog, e.g. `September 22, 2023`.
+func (r Release) GetDate() string {
+	return r.date
+}
+
+func (r Release) GetLink() string {
+	return r.link
+}
+
+func (r *Release) AddEntry(entry *Entry) {
+	r.entries = append(r.entries, entry)
+}
+
+func (r Release) GetEntries() []*Entry {
+	return r.entries
+}
+
+func NewRelease(date, link string) (*Release, error) {
+	if strings.TrimSpace(date) == "" {
+		return nil, errors.New("date is required")
+	}
+
+	if _, err := url.ParseRequestURI(link); err != nil {
+		return nil, errors.New("link is invalid URI for request")
+	}
+
+	return &Release{date: date, link: link}, nil
+}
+
+// Entry is the bullet of the release or the paragraph between bullets. Nested bullets are entries of the entry.
+type Entry struct {
+	text       string
+	references []*Reference
+	entries    []*Entry
+}
+
+func (e Entry) GetText() string {
+	return e.text
+}
+
+func (e *Entry) AddReference(reference *Reference) {
+	e.references = append(e.references, reference)
+}
+
+func (e Entry) GetReferences() []*Reference {
+	return e.references
+}
+
+func (e *Entry) AddEntry(entry *Entry) {
+	e.entries = append(e.entries, entry)
+}
+
+func (e Entry) GetEntries() []*Entry {
+	return e.entries
+}
+
+func NewEntry(text string) (*Entry, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, errors.New("text is required")
+	}
+
+	return &Entry{text: text}, nil
+}
+
+type ReferenceKind string
+
+const (
+	ReferenceType   ReferenceKind = "type"
+	ReferenceMethod ReferenceKind = "method"
+)
+
+// Reference is the link of the entry. Links to types and methods are resolved by Changelog.ResolveReferences.
+type Reference struct {
+	text string
+	link string
+	kind ReferenceKind
+	name string
+}
+
+func (r Reference) GetText() string {
+	return r.text
+}
+
+func (r Reference) GetLink() string {
+	return r.link
+}
+
+// GetAnchor returns the anchor of the API doc which the link points to or an empty string for other links.
+func (r Reference) GetAnchor() string {
+	if !strings.HasPrefix(r.link, ApiDocLink+"#") {
+		return ""
+	}
+
+	return anchorOf(r.link)
+}
+
+// GetKind returns an empty string if the reference isn't resolved.
+func (r Reference) GetKind() ReferenceKind {
+	return r.kind
+}
+
+func (r Reference) GetName() string {
+	return r.name
+}
+
+func NewReference(text, link string) (*Reference, error) {
+	if _, err := url.ParseRequestURI(link); err != nil {
+		return nil, errors.New("link is invalid URI for request")
+	}
+
+	return &Reference{text: strings.TrimSpace(text), link: link}, nil
+}
+
+func anchorOf(link string) string {
+	if i := strings.LastIndex(link, "#"); i >= 0 {
+		return link[i+1:]
+	}
+
+	return ""
+}
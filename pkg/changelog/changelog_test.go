@@ -0,0 +1,101 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+type changelogFunc func(cl *Changelog) error
+
+func (f changelogFunc) FillChangelog(cl *Changelog) error {
+	return f(cl)
+}
+
+func TestGetAnchor(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{"https://core.telegram.org/bots/api#message", "message"},
+		{"https://core.telegram.org/bots/api", ""},
+		{"https://core.telegram.org/bots/features#inline-requests", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			r, err := NewReference("text", tt.link)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.GetAnchor(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveReferences(t *testing.T) {
+	as := spectest.New(t, func(as *spec.ApiSpec) error {
+		spectest.AddType(as, "Message")
+		spectest.AddMethod(as, "sendMessage", "Message")
+
+		return nil
+	})
+
+	links := []string{
+		"https://core.telegram.org/bots/api#Message",
+		"https://core.telegram.org/bots/api#sendmessage",
+		"https://core.telegram.org/bots/api#formatting-options",
+		"https://core.telegram.org/bots/features",
+	}
+	references := make([]*Reference, len(links))
+
+	cl, err := NewChangelog(changelogFunc(func(cl *Changelog) error {
+		release, _ := NewRelease("December 29, 2023", "https://core.telegram.org/bots/api-changelog#december-29-2023")
+		release.SetVersion("7.0")
+		entry, _ := NewEntry("Added the method sendMessage.")
+		nested, _ := NewEntry("Nested entry.")
+		for i, link := range links {
+			references[i], _ = NewReference("text", link)
+			if i%2 == 0 {
+				entry.AddReference(references[i])
+			} else {
+				nested.AddReference(references[i])
+			}
+		}
+		entry.AddEntry(nested)
+		release.AddEntry(entry)
+
+		return cl.AddRelease(release)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unresolved := cl.ResolveReferences(as)
+	if len(unresolved) != 1 || unresolved[0] != references[2] {
+		t.Errorf("expected the unresolved anchor formatting-options, got %v", unresolved)
+	}
+
+	tests := []struct {
+		name      string
+		reference *Reference
+		kind      ReferenceKind
+		element   string
+	}{
+		{"type", references[0], ReferenceType, "Message"},
+		{"method of the nested entry", references[1], ReferenceMethod, "sendMessage"},
+		{"unknown anchor", references[2], "", ""},
+		{"other page", references[3], "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.reference.GetKind() != tt.kind || tt.reference.GetName() != tt.element {
+				t.Errorf("got %s %s, want %s %s", tt.reference.GetKind(), tt.reference.GetName(), tt.kind, tt.element)
+			}
+		})
+	}
+}
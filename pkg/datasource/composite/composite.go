@@ -0,0 +1,274 @@
+package datasource_composite
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+type Strategy string
+
+const (
+	// FirstWins keeps the element filled by the earlier source.
+	FirstWins Strategy = "first-wins"
+	// LastWins replaces the element by the one filled by the later source.
+	LastWins Strategy = "last-wins"
+	// ErrorOnConflict fails if several sources fill the element.
+	ErrorOnConflict Strategy = "error"
+)
+
+func ParseStrategy(s string) (Strategy, error) {
+	switch strategy := Strategy(s); strategy {
+	case FirstWins, LastWins, ErrorOnConflict:
+		return strategy, nil
+	}
+
+	return "", errors.New("unknown merge strategy: " + s)
+}
+
+// Patcher changes the spec which is already filled by previous sources, e.g. datasource_overlay.Overlay.
+type Patcher interface {
+	Apply(as *spec.ApiSpec) error
+}
+
+type step struct {
+	name    string
+	source  spec.DataSource
+	patcher Patcher
+}
+
+// DatasourceComposite runs several sources in order. Every source fills its own spec, which is merged into the resulting one
+// element by element: the version, the release date, the link, types, methods and aliases.
+type DatasourceComposite struct {
+	steps      []step
+	strategy   Strategy
+	strategies map[string]Strategy
+}
+
+// AddSource adds the source with the name which is used in conflict reports.
+func (dc *DatasourceComposite) AddSource(name string, source spec.DataSource) error {
+	if source == nil {
+		return errors.New("data source is required")
+	}
+
+	dc.steps = append(dc.steps, step{name: name, source: source})
+
+	return nil
+}
+
+// AddPatcher adds the patcher which is applied to the result of all the sources added before it.
+func (dc *DatasourceComposite) AddPatcher(name string, patcher Patcher) error {
+	if patcher == nil {
+		return errors.New("patcher is required")
+	}
+
+	dc.steps = append(dc.steps, step{name: name, patcher: patcher})
+
+	return nil
+}
+
+// SetStrategy sets the strategy for elements under the path, e.g. `types` or `methods.sendMessage`. The longest path wins.
+func (dc *DatasourceComposite) SetStrategy(path string, strategy Strategy) {
+	dc.strategies[path] = strategy
+}
+
+func (dc DatasourceComposite) getStrategy(path string) Strategy {
+	strategy, matched := dc.strategy, ""
+	for prefix, s := range dc.strategies {
+		if (path == prefix || strings.HasPrefix(path, prefix+".")) && len(prefix) > len(matched) {
+			strategy, matched = s, prefix
+		}
+	}
+
+	return strategy
+}
+
+func (dc *DatasourceComposite) FillApiSpec(as *spec.ApiSpec) error {
+	if len(dc.steps) == 0 {
+		return errors.New("no data sources")
+	}
+
+	m := &merger{composite: dc, target: as, owners: make(map[string]string)}
+	for _, s := range dc.steps {
+		if s.patcher != nil {
+			// Patchers expect the merged spec, so they aren't applied to the spec with unresolved conflicts.
+			if len(m.conflicts) != 0 {
+				break
+			}
+			if err := s.patcher.Apply(as); err != nil {
+				return errors.New(s.name + ": " + err.Error())
+			}
+			continue
+		}
+
+		filled, err := spec.NewApiSpec(s.source)
+		if err != nil {
+			return errors.New(s.name + ": " + err.Error())
+		}
+
+		if err := m.merge(s.name, filled); err != nil {
+			return err
+		}
+	}
+
+	if len(m.conflicts) != 0 {
+		return spec.NewCompositeError(m.conflicts)
+	}
+
+	return nil
+}
+
+func NewDatasourceComposite(strategy Strategy) (*DatasourceComposite, error) {
+	if _, err := ParseStrategy(string(strategy)); err != nil {
+		return nil, err
+	}
+
+	return &DatasourceComposite{strategy: strategy, strategies: make(map[string]Strategy)}, nil
+}
+
+// merger copies elements of filled specs into the target one and remembers which source each element came from.
+type merger struct {
+	composite *DatasourceComposite
+	target    *spec.ApiSpec
+	owners    map[string]string
+	conflicts []error
+}
+
+// take reports whether the element of the source must be taken. Conflicts are collected to report all of them at once.
+func (m *merger) take(path, source string, exists bool) bool {
+	if !exists {
+		m.owners[path] = source
+		return true
+	}
+
+	owner, owned := m.owners[path]
+	if !owned {
+		owner = "a patcher"
+	}
+
+	switch m.composite.getStrategy(path) {
+	case FirstWins:
+		return false
+	case LastWins:
+		m.owners[path] = source
+		return true
+	}
+
+	m.conflicts = append(m.conflicts, errors.New(fmt.Sprintf("conflict at %s: filled by %s and %s", path, owner, source)))
+
+	return false
+}
+
+func (m *merger) merge(source string, from *spec.ApiSpec) error {
+	m.mergeMeta(source, "version", m.target.GetVersion(), from.GetVersion(), m.target.SetVersion)
+	m.mergeMeta(source, "releaseDate", m.target.GetReleaseDate(), from.GetReleaseDate(), m.target.SetReleaseDate)
+	m.mergeMeta(source, "link", m.target.GetLink(), from.GetLink(), m.target.SetLink)
+
+	var taken []*spec.TgTypeSpec
+	// Replaced types lose their relations, so they're remembered to link the types which are kept in the target.
+	replaced := make(map[string][]*spec.TgTypeSpec)
+	for _, t := range from.GetOrderedTypes(spec.OrderAlphabetical) {
+		current, exists := m.target.GetType(t.GetName())
+		if !m.take("types."+t.GetName(), source, exists) {
+			continue
+		}
+
+		if exists {
+			replaced[t.GetName()] = []*spec.TgTypeSpec{current}
+		}
+
+		m.target.RemoveType(t.GetName())
+		m.target.AddType(m.target.CopyType(t))
+		taken = append(taken, t)
+	}
+
+	// Relations are restored after all the types are taken, so both ends can be found.
+	for _, t := range taken {
+		copied, _ := m.target.GetType(t.GetName())
+		for _, origin := range append(replaced[t.GetName()], t) {
+			for _, parent := range origin.GetParents() {
+				if p, exists := m.target.GetType(parent.GetName()); exists {
+					p.AddChild(copied)
+					copied.AddParent(p)
+				}
+			}
+			for _, child := range origin.GetChildren() {
+				if c, exists := m.target.GetType(child.GetName()); exists {
+					copied.AddChild(c)
+					c.AddParent(copied)
+				}
+			}
+		}
+	}
+
+	for _, method := range from.GetOrderedMethods(spec.OrderAlphabetical) {
+		_, exists := m.target.GetMethod(method.GetName())
+		if !m.take("methods."+method.GetName(), source, exists) {
+			continue
+		}
+
+		copied, err := m.target.CopyMethod(method)
+		if err != nil {
+			return errors.New(source + ": " + err.Error())
+		}
+		m.target.AddMethod(copied)
+	}
+
+	return m.mergeAliases(source, from.GetAliases())
+}
+
+// mergeMeta treats equal values as no conflict, because every source of a full spec has them.
+func (m *merger) mergeMeta(source, path, current, value string, set func(string) error) {
+	if value == "" || value == current {
+		return
+	}
+
+	if m.take(path, source, current != "") {
+		set(value)
+	}
+}
+
+// mergeAliases treats aliases of the same union as no conflict. The union can have only one alias,
+// so aliases of the same union with different names conflict too.
+func (m *merger) mergeAliases(source string, aliases map[string]*spec.DataTypeAlias) error {
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		definition := aliases[name].GetDataType().GetDefinition()
+		union, _ := m.target.DeclareDataType(definition).(*spec.UnionDataType)
+
+		current, exists := m.target.GetAlias(name)
+		if exists && current.GetDataType() == union {
+			continue
+		}
+
+		other := union.GetAlias()
+		if other != nil && !exists {
+			if owner, owned := m.owners["aliases."+other.GetName()]; owned {
+				m.owners["aliases."+name] = owner
+			}
+		}
+
+		if !m.take("aliases."+name, source, exists || other != nil) {
+			continue
+		}
+
+		m.target.RemoveAlias(name)
+		if other != nil {
+			m.target.RemoveAlias(other.GetName())
+		}
+
+		if _, err := m.target.DeclareAlias(name, definition); err != nil {
+			return errors.New(source + ": " + err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package datasource_composite
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+type patchFunc func(as *spec.ApiSpec) error
+
+func (f patchFunc) Apply(as *spec.ApiSpec) error {
+	return f(as)
+}
+
+// source fills the `Chat` type with the description and the `getMe` method which returns `Chat`.
+func source(description string) spec.DataSource {
+	return spectest.Fill(func(as *spec.ApiSpec) error {
+		spectest.SetRelease(as, "7.0")
+
+		spectest.AddType(as, "Chat", "id int64").SetDescription(description)
+		spectest.AddMethod(as, "getMe", "Chat").SetDescription(description)
+
+		return nil
+	})
+}
+
+func newComposite(t *testing.T, strategy Strategy, sources ...spec.DataSource) *DatasourceComposite {
+	t.Helper()
+
+	dc, err := NewDatasourceComposite(strategy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range sources {
+		if err := dc.AddSource(string(rune('a'+i)), s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dc
+}
+
+func TestStrategies(t *testing.T) {
+	tests := []struct {
+		strategy    Strategy
+		description string
+		err         string
+	}{
+		{FirstWins, "first", ""},
+		{LastWins, "second", ""},
+		{ErrorOnConflict, "", "conflict at methods.getMe: filled by a and b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			as, err := spec.NewApiSpec(newComposite(t, tt.strategy, source("first"), source("second")))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) || !strings.Contains(err.Error(), "conflict at types.Chat") {
+					t.Fatalf("expected conflicts, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			chat, _ := as.GetType("Chat")
+			method, _ := as.GetMethod("getMe")
+			if chat.GetDescription() != tt.description || method.GetDescription() != tt.description {
+				t.Errorf("got %s and %s, want %s", chat.GetDescription(), method.GetDescription(), tt.description)
+			}
+			if err := as.SelfCheck(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestStrategyOfPath(t *testing.T) {
+	dc := newComposite(t, ErrorOnConflict, source("first"), source("second"))
+	dc.SetStrategy("types", FirstWins)
+	dc.SetStrategy("types.Chat", LastWins)
+	dc.SetStrategy("methods", FirstWins)
+
+	as, err := spec.NewApiSpec(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chat, _ := as.GetType("Chat")
+	method, _ := as.GetMethod("getMe")
+	if chat.GetDescription() != "second" || method.GetDescription() != "first" {
+		t.Errorf("got %s and %s, want second and first", chat.GetDescription(), method.GetDescription())
+	}
+}
+
+func TestLastWinsKeepsRelations(t *testing.T) {
+	parent := spectest.Fill(func(as *spec.ApiSpec) error {
+		spectest.AddChildren(spectest.AddType(as, "MaybeInaccessibleMessage"), spectest.AddType(as, "Message"))
+
+		return nil
+	})
+	// The second source has Message without the parent, so the relation is known only to the replaced type.
+	child := spectest.Fill(func(as *spec.ApiSpec) error {
+		spectest.AddType(as, "Message").SetDescription("replaced")
+
+		return nil
+	})
+
+	as, err := spec.NewApiSpec(newComposite(t, LastWins, parent, child))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message, _ := as.GetType("Message")
+	maybe, _ := as.GetType("MaybeInaccessibleMessage")
+	if message.GetDescription() != "replaced" {
+		t.Errorf("Message isn't replaced")
+	}
+	if len(message.GetParents()) != 1 || message.GetParents()[0] != maybe {
+		t.Errorf("Message lost the parent: %v", message.GetParents())
+	}
+	if len(maybe.GetChildren()) != 1 || maybe.GetChildren()[0] != message {
+		t.Errorf("MaybeInaccessibleMessage lost the child: %v", maybe.GetChildren())
+	}
+}
+
+func TestPatchers(t *testing.T) {
+	dc := newComposite(t, FirstWins, source("first"))
+	dc.AddPatcher("patch", patchFunc(func(as *spec.ApiSpec) error {
+		chat, _ := as.GetType("Chat")
+		chat.SetDescription("patched")
+
+		return nil
+	}))
+	dc.AddSource("late", source("late"))
+
+	as, err := spec.NewApiSpec(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if chat, _ := as.GetType("Chat"); chat.GetDescription() != "patched" {
+		t.Errorf("got %s, want patched", chat.GetDescription())
+	}
+}
+
+func TestRejectsMissingSteps(t *testing.T) {
+	dc := newComposite(t, FirstWins)
+	if err := dc.AddSource("nil", nil); err == nil {
+		t.Error("expected the error for the nil source")
+	}
+	if err := dc.AddPatcher("nil", nil); err == nil {
+		t.Error("expected the error for the nil patcher")
+	}
+	if _, err := spec.NewApiSpec(dc); err == nil {
+		t.Error("expected the error for no sources")
+	}
+}
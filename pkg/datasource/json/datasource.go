@@ -3,6 +3,7 @@ package datasource_json
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 
 	export_to_json "github.com/alserom/tg-bot-api-spec/pkg/export/json"
@@ -57,7 +58,11 @@ func (dj *DatasourceJson) FillApiSpec(as *spec.ApiSpec) error {
 		}
 	}
 
-	return lastErr
+	if lastErr != nil {
+		return lastErr
+	}
+
+	return addAliases(as, dj.jsonData.Aliases)
 }
 
 func NewDatasourceJson(path string) (*DatasourceJson, error) {
@@ -81,113 +86,209 @@ func NewDatasourceJson(path string) (*DatasourceJson, error) {
 }
 
 func addTgTypes(as *spec.ApiSpec, types map[string]export_to_json.TgType, ch chan<- error) {
-	deferParent := make(map[string][]*spec.TgTypeSpec)
-	deferChild := make(map[string]*spec.TgTypeSpec)
-
 	for _, t := range types {
-		tgType, err := spec.NewTgTypeSpec(t.Category, t.Name, t.Link)
+		tgType, err := NewTgType(as, t)
 		if err != nil {
 			ch <- err
 			return
 		}
 
-		tgType.SetDescription(t.Description)
-
-		if t.Parent != nil {
-			parent, exists := as.GetType(string(*t.Parent))
-			if !exists {
-				deferParent[string(*t.Parent)] = append(deferParent[string(*t.Parent)], tgType)
-			} else {
-				tgType.SetParent(parent)
-			}
-		}
+		as.AddType(tgType)
+	}
 
+	// Relations are added after all the types. Every end of the relation is read from its own list,
+	// so the relation which is written only on one side of the file is kept asymmetric and reported by the spec check.
+	for _, t := range types {
+		tgType, _ := as.GetType(t.Name)
 		for _, cn := range t.Children {
 			child, exists := as.GetType(cn)
 			if !exists {
-				deferChild[cn] = tgType
-			} else {
-				tgType.AddChild(child)
+				ch <- errors.New(fmt.Sprintf("child type %s of %s missed", cn, t.Name))
+				return
 			}
+			tgType.AddChild(child)
 		}
 
-		for _, p := range t.Properties {
-			tgTypeProperty, err := spec.NewTgTypeSpecProperty(p.Name)
-			if err != nil {
-				ch <- err
+		for _, pn := range parentNames(t) {
+			parent, exists := as.GetType(pn)
+			if !exists {
+				ch <- errors.New(fmt.Sprintf("parent type %s of %s missed", pn, t.Name))
 				return
 			}
+			tgType.AddParent(parent)
+		}
+	}
+}
 
-			tgTypeProperty.SetDescription(p.Description)
-			tgTypeProperty.SetOptional(p.Optional)
-
-			if p.PredefinedValue != nil {
-				value := spec.TgTypeSpecPropertyValue(*p.PredefinedValue)
-				tgTypeProperty.SetPredefinedValue(&value)
-			}
+// parentNames returns the parents of the type. Files of old versions use the single `parent` field instead of `parents`.
+func parentNames(t export_to_json.TgType) []string {
+	names := t.Parents
+	if t.Parent != nil {
+		names = append(names, string(*t.Parent))
+	}
 
-			for _, dt := range p.Types {
-				tgTypeProperty.AddDataType(as.DeclareDataType(dt))
-			}
+	return names
+}
 
-			tgType.AddProperty(tgTypeProperty)
+func addTgMethods(as *spec.ApiSpec, methods map[string]export_to_json.TgMethod, ch chan<- error) {
+	for _, m := range methods {
+		tgMethod, err := NewTgMethod(as, m)
+		if err != nil {
+			ch <- err
+			return
 		}
 
-		as.AddType(tgType)
+		as.AddMethod(tgMethod)
 	}
+}
 
-	for parentName, childs := range deferParent {
-		parent, exists := as.GetType(parentName)
-		if !exists {
-			ch <- errors.New("parent type missed")
-			return
-		}
-		for _, child := range childs {
-			child.SetParent(parent)
+func addAliases(as *spec.ApiSpec, aliases map[string]export_to_json.DataTypeAlias) error {
+	for _, a := range aliases {
+		if _, err := as.DeclareAlias(a.Name, a.Types...); err != nil {
+			return err
 		}
 	}
 
-	for childName, parent := range deferChild {
-		child, exists := as.GetType(childName)
-		if !exists {
-			ch <- errors.New("child type missed")
-			return
+	return nil
+}
+
+// NewTgType creates the type with its properties. Relations aren't added, because parents and children may be not created yet.
+func NewTgType(as *spec.ApiSpec, t export_to_json.TgType) (*spec.TgTypeSpec, error) {
+	tgType, err := spec.NewTgTypeSpec(t.Category, t.Name, t.Link)
+	if err != nil {
+		return nil, err
+	}
+
+	tgType.SetDescription(t.Description)
+	tgType.SetOrder(t.Order)
+	tgType.SetHistory(newHistory(t.Since, t.LastChanged, t.Until))
+
+	for _, p := range t.Properties {
+		tgTypeProperty, err := NewTgTypeProperty(as, p)
+		if err != nil {
+			return nil, err
 		}
-		parent.AddChild(child)
+
+		tgType.AddProperty(tgTypeProperty)
 	}
+
+	return tgType, nil
 }
 
-func addTgMethods(as *spec.ApiSpec, methods map[string]export_to_json.TgMethod, ch chan<- error) {
-	for _, m := range methods {
-		tgMethod, err := spec.NewTgMethodSpec(m.Category, m.Name, m.Link)
+func NewTgTypeProperty(as *spec.ApiSpec, p export_to_json.TgTypeProperty) (*spec.TgTypeSpecProperty, error) {
+	tgTypeProperty, err := spec.NewTgTypeSpecProperty(p.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	tgTypeProperty.SetDescription(p.Description)
+	tgTypeProperty.SetOptional(p.Optional)
+	tgTypeProperty.SetOrder(p.Order)
+	tgTypeProperty.SetHistory(newHistory(p.Since, p.LastChanged, p.Until))
+	tgTypeProperty.SetEnum(p.Enum)
+	tgTypeProperty.SetConstraints(newConstraints(p.MinLength, p.MaxLength, p.Minimum, p.Maximum))
+
+	if p.PredefinedValue != nil {
+		value := spec.TgTypeSpecPropertyValue(*p.PredefinedValue)
+		tgTypeProperty.SetPredefinedValue(&value)
+	}
+
+	if len(p.Types) > 0 {
+		tgTypeProperty.AddDataType(as.DeclareUnionDataType(p.Types...))
+	}
+
+	return tgTypeProperty, nil
+}
+
+func NewTgMethod(as *spec.ApiSpec, m export_to_json.TgMethod) (*spec.TgMethodSpec, error) {
+	tgMethod, err := spec.NewTgMethodSpec(m.Category, m.Name, m.Link)
+	if err != nil {
+		return nil, err
+	}
+
+	tgMethod.SetDescription(m.Description)
+	tgMethod.SetOrder(m.Order)
+	tgMethod.SetHistory(newHistory(m.Since, m.LastChanged, m.Until))
+
+	if len(m.Returns) > 0 {
+		tgMethod.AddReturnType(as.DeclareUnionDataType(m.Returns...))
+	}
+
+	for _, a := range m.Arguments {
+		arg, err := NewTgMethodArgument(as, a)
 		if err != nil {
-			ch <- err
-			return
+			return nil, err
 		}
 
-		tgMethod.SetDescription(m.Description)
+		tgMethod.AddArgument(arg)
+	}
 
-		for _, dt := range m.Returns {
-			tgMethod.AddReturnType(as.DeclareDataType(dt))
+	for _, cr := range m.ConditionalReturns {
+		conditionalReturn, err := spec.NewTgMethodSpecConditionalReturn(as.DeclareDataType(cr.Type), cr.Condition)
+		if err != nil {
+			return nil, err
 		}
 
-		for _, a := range m.Arguments {
-			arg, err := spec.NewTgMethodSpecArgument(a.Name)
-			if err != nil {
-				ch <- err
-				return
-			}
+		conditionalReturn.SetArguments(cr.Arguments)
+		tgMethod.AddConditionalReturn(conditionalReturn)
+	}
+
+	for _, g := range m.ArgumentGroups {
+		group, err := spec.NewTgMethodSpecArgumentGroup(spec.ArgumentGroupKind(g.Kind), g.Arguments, g.DependsOn)
+		if err != nil {
+			return nil, err
+		}
 
-			arg.SetDescription(a.Description)
-			arg.SetRequired(a.Required)
+		tgMethod.AddArgumentGroup(group)
+	}
 
-			for _, dt := range a.Types {
-				arg.AddDataType(as.DeclareDataType(dt))
-			}
+	return tgMethod, nil
+}
 
-			tgMethod.AddArgument(arg)
-		}
+func NewTgMethodArgument(as *spec.ApiSpec, a export_to_json.TgMethodArgument) (*spec.TgMethodSpecArgument, error) {
+	arg, err := spec.NewTgMethodSpecArgument(a.Name)
+	if err != nil {
+		return nil, err
+	}
 
-		as.AddMethod(tgMethod)
+	arg.SetDescription(a.Description)
+	arg.SetRequired(a.Required)
+	arg.SetOrder(a.Order)
+	arg.SetHistory(newHistory(a.Since, a.LastChanged, a.Until))
+	arg.SetEnum(a.Enum)
+	arg.SetConstraints(newConstraints(a.MinLength, a.MaxLength, a.Minimum, a.Maximum))
+
+	if len(a.Types) > 0 {
+		arg.AddDataType(as.DeclareUnionDataType(a.Types...))
+	}
+
+	if err := arg.SetDefault(a.Default); err != nil {
+		return nil, err
+	}
+
+	return arg, nil
+}
+
+func newHistory(since, lastChanged, until string) *spec.VersionHistory {
+	history := &spec.VersionHistory{Since: since, LastChanged: lastChanged, Until: until}
+	if history.IsEmpty() {
+		return nil
+	}
+
+	return history
+}
+
+func newConstraints(minLength, maxLength *int, minimum, maximum *float64) *spec.ValueConstraints {
+	constraints := &spec.ValueConstraints{
+		MinLength: minLength,
+		MaxLength: maxLength,
+		Minimum:   minimum,
+		Maximum:   maximum,
 	}
+
+	if constraints.IsEmpty() {
+		return nil
+	}
+
+	return constraints
 }
@@ -0,0 +1,261 @@
+package datasource_json
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	export_to_json "github.com/alserom/tg-bot-api-spec/pkg/export/json"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+// readSpec reads the spec from the file with the provided content.
+func readSpec(t *testing.T, content string) *spec.ApiSpec {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "spec.json")
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ds, err := NewDatasourceJson(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	as, err := spec.NewApiSpec(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return as
+}
+
+// roundTrip exports the spec to the file and reads it back.
+func roundTrip(t *testing.T, origin *spec.ApiSpec) *spec.ApiSpec {
+	t.Helper()
+
+	je, err := export_to_json.NewApiSpecExporter(*origin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "spec.json")
+	if err := je.Export(file); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return readSpec(t, string(content))
+}
+
+// relations returns the sorted names of parents and children of the type.
+func relations(t *testing.T, as *spec.ApiSpec, name string) (string, string) {
+	t.Helper()
+
+	tgType, exists := as.GetType(name)
+	if !exists {
+		t.Fatalf("type %s doesn't exist", name)
+	}
+
+	names := func(types []*spec.TgTypeSpec) string {
+		var names []string
+		for _, t := range types {
+			names = append(names, t.GetName())
+		}
+		sort.Strings(names)
+
+		return strings.Join(names, ",")
+	}
+
+	return names(tgType.GetParents()), names(tgType.GetChildren())
+}
+
+func hasDiagnostic(as *spec.ApiSpec, code spec.DiagnosticCode) bool {
+	for _, d := range as.Diagnose() {
+		if d.Code == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestReadDeprecatedParent(t *testing.T) {
+	as := readSpec(t, `{
+		"version": "6.9",
+		"releaseDate": "September 22, 2023",
+		"link": "https://core.telegram.org/bots/api-changelog#september-22-2023",
+		"types": {
+			"ChatMember": {
+				"category": "available-types",
+				"name": "ChatMember",
+				"link": "https://core.telegram.org/bots/api#chatmember",
+				"description": "This object contains information about one member of a chat.",
+				"children": ["ChatMemberOwner"]
+			},
+			"ChatMemberOwner": {
+				"category": "available-types",
+				"name": "ChatMemberOwner",
+				"link": "https://core.telegram.org/bots/api#chatmemberowner",
+				"description": "Represents a chat member that owns the chat.",
+				"parent": "ChatMember",
+				"properties": [
+					{"name": "status", "description": "The member's status in the chat, always “creator”", "types": ["string"], "optional": false, "default": "creator"}
+				]
+			}
+		},
+		"methods": {
+			"logOut": {
+				"category": "available-methods",
+				"name": "logOut",
+				"link": "https://core.telegram.org/bots/api#logout",
+				"description": "Use this method to log out from the cloud Bot API server before launching the bot locally.",
+				"returns": ["true"]
+			}
+		}
+	}`)
+
+	if parents, _ := relations(t, as, "ChatMemberOwner"); parents != "ChatMember" {
+		t.Errorf("got parents %q, want ChatMember", parents)
+	}
+	if _, children := relations(t, as, "ChatMember"); children != "ChatMemberOwner" {
+		t.Errorf("got children %q, want ChatMemberOwner", children)
+	}
+	if hasDiagnostic(as, spec.CodeAsymmetricRelation) {
+		t.Error("unexpected asymmetric relation")
+	}
+}
+
+func TestMultipleParentsRoundTrip(t *testing.T) {
+	origin := spectest.New(t, func(as *spec.ApiSpec) error {
+		spectest.SetRelease(as, "7.0")
+		message := spectest.AddType(as, "Message", "message_id int32")
+		spectest.AddChildren(spectest.AddType(as, "MaybeInaccessibleMessage"), message)
+		spectest.AddChildren(spectest.AddType(as, "ReplyTarget"), message)
+		spectest.AddMethod(as, "logOut", "true")
+
+		return nil
+	})
+
+	as := roundTrip(t, origin)
+
+	if parents, _ := relations(t, as, "Message"); parents != "MaybeInaccessibleMessage,ReplyTarget" {
+		t.Errorf("got parents %q, want MaybeInaccessibleMessage,ReplyTarget", parents)
+	}
+	for _, name := range []string{"MaybeInaccessibleMessage", "ReplyTarget"} {
+		if _, children := relations(t, as, name); children != "Message" {
+			t.Errorf("got children %q of %s, want Message", children, name)
+		}
+	}
+	if hasDiagnostic(as, spec.CodeAsymmetricRelation) {
+		t.Error("unexpected asymmetric relation")
+	}
+}
+
+func TestReadAsymmetricRelation(t *testing.T) {
+	as := readSpec(t, `{
+		"version": "7.0",
+		"releaseDate": "December 29, 2023",
+		"link": "https://core.telegram.org/bots/api-changelog#december-29-2023",
+		"types": {
+			"MaybeInaccessibleMessage": {
+				"category": "available-types",
+				"name": "MaybeInaccessibleMessage",
+				"link": "https://core.telegram.org/bots/api#maybeinaccessiblemessage",
+				"description": "This object describes a message that can be inaccessible to the bot.",
+				"children": ["Message"]
+			},
+			"Message": {
+				"category": "available-types",
+				"name": "Message",
+				"link": "https://core.telegram.org/bots/api#message",
+				"description": "This object represents a message.",
+				"properties": [
+					{"name": "message_id", "description": "Unique message identifier inside this chat", "types": ["int32"], "optional": false}
+				]
+			}
+		},
+		"methods": {
+			"logOut": {
+				"category": "available-methods",
+				"name": "logOut",
+				"link": "https://core.telegram.org/bots/api#logout",
+				"description": "Use this method to log out from the cloud Bot API server before launching the bot locally.",
+				"returns": ["true"]
+			}
+		}
+	}`)
+
+	if parents, _ := relations(t, as, "Message"); parents != "" {
+		t.Errorf("got parents %q, want the relation kept asymmetric", parents)
+	}
+	if !hasDiagnostic(as, spec.CodeAsymmetricRelation) {
+		t.Errorf("expected the %s diagnostic", spec.CodeAsymmetricRelation)
+	}
+}
+
+func TestLiteralTrueRoundTrip(t *testing.T) {
+	origin := spectest.New(t, func(as *spec.ApiSpec) error {
+		spectest.SetRelease(as, "7.0")
+		spectest.AddType(as, "Message", "message_id int32")
+		spectest.AddType(as, "ChatMemberRestricted", "is_member true", "can_send_messages boolean")
+		spectest.AddMethod(as, "logOut", "true")
+		spectest.AddMethod(as, "editMessageText", "Message|true", "text string")
+
+		return nil
+	})
+
+	as := roundTrip(t, origin)
+
+	chatMember, _ := as.GetType("ChatMemberRestricted")
+	properties := make(map[string]spec.DataTypeDefinition)
+	for _, p := range chatMember.GetProperties() {
+		properties[p.GetName()] = p.GetDataTypes()[0]
+	}
+	logOut, _ := as.GetMethod("logOut")
+	editMessageText, _ := as.GetMethod("editMessageText")
+
+	tests := []struct {
+		name     string
+		dataType spec.DataTypeDefinition
+		want     string
+		literal  bool
+	}{
+		{"property", properties["is_member"], "true", true},
+		{"boolean property", properties["can_send_messages"], "boolean", false},
+		{"return type", logOut.GetReturnTypes()[0], "true", true},
+		{"union return type", editMessageText.GetReturnTypes()[0], "Message|true", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.dataType.GetDefinition() != tt.want {
+				t.Fatalf("got %s, want %s", tt.dataType.GetDefinition(), tt.want)
+			}
+
+			scalar, ok := tt.dataType.(*spec.ScalarDataType)
+			if literal := ok && scalar.IsLiteral(); literal != tt.literal {
+				t.Errorf("got literal %t, want %t", literal, tt.literal)
+			}
+		})
+	}
+
+	union, ok := editMessageText.GetReturnTypes()[0].(*spec.UnionDataType)
+	if !ok {
+		t.Fatalf("expected the union, got %T", editMessageText.GetReturnTypes()[0])
+	}
+	var literals int
+	for _, member := range union.GetMemberDataTypes() {
+		if scalar, ok := member.(*spec.ScalarDataType); ok && scalar.IsLiteral() {
+			literals++
+		}
+	}
+	if literals != 1 {
+		t.Errorf("expected the literal true member of %s", union.GetDefinition())
+	}
+}
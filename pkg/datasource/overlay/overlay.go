@@ -0,0 +1,119 @@
+package datasource_overlay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+type Operation string
+
+const (
+	// OpAdd adds the element which must not exist yet. For lists of data types and enum values, it appends the values.
+	OpAdd Operation = "add"
+	// OpReplace replaces the existing element or value.
+	OpReplace Operation = "replace"
+	// OpRemove removes the existing element. For lists of data types, it removes only the listed values.
+	OpRemove Operation = "remove"
+)
+
+// Patch changes the element of the spec at the path, e.g. `methods.getMe.returns` or `types.Message.properties.chat`.
+// Values of elements have the same format as in the exported JSON file.
+type Patch struct {
+	Op    Operation       `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Overlay is the list of corrections which are applied to the spec after it's filled by the data source.
+type Overlay struct {
+	Description string  `json:"description,omitempty"`
+	Patches     []Patch `json:"patches"`
+	name        string
+}
+
+func (o Overlay) GetName() string {
+	return o.name
+}
+
+// Apply applies patches in order. Every patch is checked against the spec changed by previous ones,
+// so all the patches with missing targets are reported together. Data types orphaned by patches are pruned.
+func (o Overlay) Apply(as *spec.ApiSpec) error {
+	var errs []error
+	for i, p := range o.Patches {
+		if err := applyPatch(as, p); err != nil {
+			errs = append(errs, errors.New(fmt.Sprintf("patch #%d (%s %s): %s", i+1, p.Op, p.Path, err.Error())))
+		}
+	}
+
+	if len(errs) != 0 {
+		return spec.NewCompositeError(errs)
+	}
+
+	as.PruneDataTypes()
+
+	return nil
+}
+
+func LoadOverlay(path string) (*Overlay, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseOverlay(path, content)
+}
+
+// ParseOverlay parses the overlay in JSON format. The name is used in error messages.
+func ParseOverlay(name string, content []byte) (*Overlay, error) {
+	var overlay Overlay
+	if err := json.Unmarshal(content, &overlay); err != nil {
+		return nil, errors.New(name + ": " + err.Error())
+	}
+	overlay.name = name
+
+	for i, p := range overlay.Patches {
+		switch p.Op {
+		case OpAdd, OpReplace, OpRemove:
+		default:
+			return nil, errors.New(fmt.Sprintf("%s: patch #%d: unknown operation '%s'", name, i+1, p.Op))
+		}
+
+		if p.Path == "" {
+			return nil, errors.New(fmt.Sprintf("%s: patch #%d: path is required", name, i+1))
+		}
+	}
+
+	return &overlay, nil
+}
+
+// DatasourceOverlay fills the spec by the source and applies overlays in order.
+type DatasourceOverlay struct {
+	source   spec.DataSource
+	overlays []*Overlay
+}
+
+func (do *DatasourceOverlay) FillApiSpec(as *spec.ApiSpec) error {
+	if err := do.source.FillApiSpec(as); err != nil {
+		return err
+	}
+
+	for _, o := range do.overlays {
+		if err := o.Apply(as); err != nil {
+			return errors.New(o.name + ": " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+func NewDatasourceOverlay(source spec.DataSource, overlays ...*Overlay) (*DatasourceOverlay, error) {
+	if source == nil {
+		return nil, errors.New("data source is required")
+	}
+
+	return &DatasourceOverlay{source: source, overlays: overlays}, nil
+}
@@ -0,0 +1,247 @@
+package datasource_overlay
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+// newSpec fills `Chat`, `Message` which refers to `Chat` and `sendMessage` which returns `Message`.
+func newSpec(t *testing.T) *spec.ApiSpec {
+	t.Helper()
+
+	return spectest.New(t, func(as *spec.ApiSpec) error {
+		spectest.SetRelease(as, "7.0")
+
+		chat := spectest.AddType(as, "Chat", "id int64", "type string")
+		chat.GetProperties()[1].SetEnum([]string{"private", "group"})
+
+		message := spectest.AddType(as, "Message", "chat Chat", "text string")
+		message.GetProperties()[1].SetOptional(true)
+
+		method := spectest.AddMethod(as, "sendMessage", "Message", "chat_id int64|string", "parse_mode string")
+		method.GetArguments()[1].SetRequired(false)
+
+		return nil
+	})
+}
+
+func getType(t *testing.T, as *spec.ApiSpec, name string) *spec.TgTypeSpec {
+	t.Helper()
+
+	tgType, exists := as.GetType(name)
+	if !exists {
+		t.Fatalf("type %s doesn't exist", name)
+	}
+
+	return tgType
+}
+
+func getMethod(t *testing.T, as *spec.ApiSpec, name string) *spec.TgMethodSpec {
+	t.Helper()
+
+	m, exists := as.GetMethod(name)
+	if !exists {
+		t.Fatalf("method %s doesn't exist", name)
+	}
+
+	return m
+}
+
+func dataTypes(dataTypes []spec.DataTypeDefinition) string {
+	var definitions []string
+	for _, dt := range dataTypes {
+		definitions = append(definitions, dt.GetDefinition())
+	}
+
+	return strings.Join(definitions, ",")
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		patches string
+		err     string
+		check   func(t *testing.T, as *spec.ApiSpec)
+	}{
+		{
+			name:    "add property",
+			patches: `[{"op": "add", "path": "types.Message.properties.date", "value": {"description": "Date", "types": ["int32"]}}]`,
+			check: func(t *testing.T, as *spec.ApiSpec) {
+				p, exists := getProperty(getType(t, as, "Message"), "date")
+				if !exists || dataTypes(p.GetDataTypes()) != "int32" {
+					t.Errorf("property isn't added")
+				}
+			},
+		},
+		{
+			name:    "add existing property",
+			patches: `[{"op": "add", "path": "types.Message.properties.text", "value": {"types": ["string"]}}]`,
+			err:     "property text already exists",
+		},
+		{
+			name:    "replace property",
+			patches: `[{"op": "replace", "path": "types.Message.properties.text", "value": {"description": "Text", "types": ["string"], "optional": false}}]`,
+			check: func(t *testing.T, as *spec.ApiSpec) {
+				message := getType(t, as, "Message")
+				p, _ := getProperty(message, "text")
+				if p.IsOptional() || p.GetDescription() != "Text" || message.GetProperties()[1] != p {
+					t.Errorf("property isn't replaced in place")
+				}
+			},
+		},
+		{
+			name:    "replace property with another name",
+			patches: `[{"op": "replace", "path": "types.Message.properties.text", "value": {"name": "caption", "types": ["string"]}}]`,
+			err:     "name caption of the value doesn't match the path",
+		},
+		{
+			name:    "remove property and type",
+			patches: `[{"op": "remove", "path": "types.Message.properties.chat"}, {"op": "remove", "path": "types.Chat"}]`,
+			check: func(t *testing.T, as *spec.ApiSpec) {
+				if _, exists := as.GetType("Chat"); exists {
+					t.Errorf("type isn't removed")
+				}
+				if _, exists := as.GetDataTypeDefinitions()["Chat"]; exists {
+					t.Errorf("data type of the removed type isn't pruned")
+				}
+			},
+		},
+		{
+			name:    "remove missing type",
+			patches: `[{"op": "remove", "path": "types.User"}]`,
+			err:     "type User doesn't exist",
+		},
+		{
+			name:    "replace type description",
+			patches: `[{"op": "replace", "path": "types.Chat.description", "value": "A chat"}]`,
+			check: func(t *testing.T, as *spec.ApiSpec) {
+				if getType(t, as, "Chat").GetDescription() != "A chat" {
+					t.Errorf("description isn't replaced")
+				}
+			},
+		},
+		{
+			name:    "add property data types",
+			patches: `[{"op": "add", "path": "types.Chat.properties.id.types", "value": ["string"]}]`,
+			check: func(t *testing.T, as *spec.ApiSpec) {
+				p, _ := getProperty(getType(t, as, "Chat"), "id")
+				if dataTypes(spec.FlattenDataTypes(p.GetDataTypes())) != "int64,string" {
+					t.Errorf("got %s", dataTypes(p.GetDataTypes()))
+				}
+			},
+		},
+		{
+			name:    "remove all the property data types",
+			patches: `[{"op": "remove", "path": "types.Chat.properties.id.types", "value": ["int64"]}]`,
+			err:     "can't remove all the data types",
+		},
+		{
+			name:    "add and remove enum",
+			patches: `[{"op": "add", "path": "types.Chat.properties.type.enum", "value": ["channel"]}, {"op": "remove", "path": "methods.sendMessage.arguments.parse_mode.enum"}]`,
+			err:     "patch #2 (remove methods.sendMessage.arguments.parse_mode.enum): enum doesn't exist",
+			check: func(t *testing.T, as *spec.ApiSpec) {
+				p, _ := getProperty(getType(t, as, "Chat"), "type")
+				if strings.Join(p.GetEnum(), ",") != "private,group,channel" {
+					t.Errorf("got %v", p.GetEnum())
+				}
+			},
+		},
+		{
+			name:    "add optional flag",
+			patches: `[{"op": "add", "path": "types.Message.properties.text.optional", "value": false}]`,
+			err:     "operation add isn't supported by the target",
+		},
+		{
+			name:    "replace argument data types and requirement",
+			patches: `[{"op": "replace", "path": "methods.sendMessage.arguments.parse_mode.types", "value": ["string", "boolean"]}, {"op": "replace", "path": "methods.sendMessage.arguments.parse_mode.required", "value": true}]`,
+			check: func(t *testing.T, as *spec.ApiSpec) {
+				a, _ := getArgument(getMethod(t, as, "sendMessage"), "parse_mode")
+				if !a.IsRequired() || len(spec.FlattenDataTypes(a.GetDataTypes())) != 2 {
+					t.Errorf("argument isn't patched: %s", dataTypes(a.GetDataTypes()))
+				}
+			},
+		},
+		{
+			name:    "add and remove arguments",
+			patches: `[{"op": "add", "path": "methods.sendMessage.arguments.text", "value": {"types": ["string"], "required": true}}, {"op": "remove", "path": "methods.sendMessage.arguments.parse_mode"}]`,
+			check: func(t *testing.T, as *spec.ApiSpec) {
+				m := getMethod(t, as, "sendMessage")
+				if _, exists := getArgument(m, "text"); !exists {
+					t.Errorf("argument isn't added")
+				}
+				if _, exists := getArgument(m, "parse_mode"); exists {
+					t.Errorf("argument isn't removed")
+				}
+			},
+		},
+		{
+			name:    "replace return types",
+			patches: `[{"op": "replace", "path": "methods.sendMessage.returns", "value": ["Message", "true"]}]`,
+			check: func(t *testing.T, as *spec.ApiSpec) {
+				if got := dataTypes(spec.FlattenDataTypes(getMethod(t, as, "sendMessage").GetReturnTypes())); got != "Message,true" {
+					t.Errorf("got %s", got)
+				}
+			},
+		},
+		{
+			name:    "remove method",
+			patches: `[{"op": "remove", "path": "methods.sendMessage"}]`,
+			check: func(t *testing.T, as *spec.ApiSpec) {
+				if _, exists := as.GetMethod("sendMessage"); exists {
+					t.Errorf("method isn't removed")
+				}
+			},
+		},
+		{
+			name:    "unknown paths",
+			patches: `[{"op": "replace", "path": "types.Chat.properties.id.default", "value": 1}, {"op": "replace", "path": "dataTypes.Chat", "value": 1}]`,
+			err:     "patch #2 (replace dataTypes.Chat): unknown path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overlay, err := ParseOverlay("test", []byte(`{"patches": `+tt.patches+`}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			as := newSpec(t)
+			err = overlay.Apply(as)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected the error '%s', got %v", tt.err, err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.check != nil {
+				tt.check(t, as)
+			}
+		})
+	}
+}
+
+func TestParseOverlay(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{"unknown operation", `{"patches": [{"op": "move", "path": "types.Chat"}]}`, "patch #1: unknown operation 'move'"},
+		{"missing path", `{"patches": [{"op": "remove"}]}`, "patch #1: path is required"},
+		{"invalid json", `{"patches": `, "test: unexpected end of JSON input"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseOverlay("test", []byte(tt.content)); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected the error '%s', got %v", tt.err, err)
+			}
+		})
+	}
+}
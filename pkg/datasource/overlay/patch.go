@@ -0,0 +1,478 @@
+package datasource_overlay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/alserom/tg-bot-api-spec/internal/utils"
+	datasource_json "github.com/alserom/tg-bot-api-spec/pkg/datasource/json"
+	export_to_json "github.com/alserom/tg-bot-api-spec/pkg/export/json"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+func applyPatch(as *spec.ApiSpec, p Patch) error {
+	parts := strings.Split(p.Path, ".")
+	switch {
+	case parts[0] == "types" && len(parts) == 2:
+		return patchType(as, parts[1], p)
+	case parts[0] == "types" && len(parts) > 2:
+		t, exists := as.GetType(parts[1])
+		if !exists {
+			return errors.New(fmt.Sprintf("type %s doesn't exist", parts[1]))
+		}
+
+		switch {
+		case len(parts) == 3 && parts[2] == "description":
+			return patchDescription(t, p)
+		case len(parts) == 4 && parts[2] == "properties":
+			return patchProperty(as, t, parts[3], p)
+		case len(parts) == 5 && parts[2] == "properties":
+			return patchPropertyAttribute(as, t, parts[3], parts[4], p)
+		}
+	case parts[0] == "methods" && len(parts) == 2:
+		return patchMethod(as, parts[1], p)
+	case parts[0] == "methods" && len(parts) > 2:
+		m, exists := as.GetMethod(parts[1])
+		if !exists {
+			return errors.New(fmt.Sprintf("method %s doesn't exist", parts[1]))
+		}
+
+		switch {
+		case len(parts) == 3 && parts[2] == "description":
+			return patchDescription(m, p)
+		case len(parts) == 3 && parts[2] == "returns":
+			return patchReturnTypes(as, m, p)
+		case len(parts) == 4 && parts[2] == "arguments":
+			return patchArgument(as, m, parts[3], p)
+		case len(parts) == 5 && parts[2] == "arguments":
+			return patchArgumentAttribute(as, m, parts[3], parts[4], p)
+		}
+	}
+
+	return errors.New("unknown path")
+}
+
+// patchType links the new type only with parents and children listed in the value, relations of the replaced type are dropped.
+func patchType(as *spec.ApiSpec, name string, p Patch) error {
+	_, exists := as.GetType(name)
+	if err := checkTarget(p.Op, exists, "type "+name); err != nil {
+		return err
+	}
+
+	if p.Op == OpRemove {
+		as.RemoveType(name)
+		return nil
+	}
+
+	var value export_to_json.TgType
+	if err := decodeValue(p, &value); err != nil {
+		return err
+	}
+	if err := checkName(&value.Name, name); err != nil {
+		return err
+	}
+
+	t, err := datasource_json.NewTgType(as, value)
+	if err != nil {
+		return err
+	}
+
+	// Relations are checked before the old type is removed, so the failed patch doesn't change the spec.
+	parents, err := getTypes(as, value.Parents, name)
+	if err != nil {
+		return err
+	}
+	children, err := getTypes(as, value.Children, name)
+	if err != nil {
+		return err
+	}
+
+	as.RemoveType(name)
+	as.AddType(t)
+
+	for _, parent := range parents {
+		parent.AddChild(t)
+		t.AddParent(parent)
+	}
+	for _, child := range children {
+		t.AddChild(child)
+		child.AddParent(t)
+	}
+
+	return nil
+}
+
+func patchProperty(as *spec.ApiSpec, t *spec.TgTypeSpec, name string, p Patch) error {
+	_, exists := getProperty(t, name)
+	if err := checkTarget(p.Op, exists, "property "+name); err != nil {
+		return err
+	}
+
+	if p.Op == OpRemove {
+		t.RemoveProperty(name)
+		return nil
+	}
+
+	var value export_to_json.TgTypeProperty
+	if err := decodeValue(p, &value); err != nil {
+		return err
+	}
+	if err := checkName(&value.Name, name); err != nil {
+		return err
+	}
+
+	property, err := datasource_json.NewTgTypeProperty(as, value)
+	if err != nil {
+		return err
+	}
+
+	if p.Op == OpAdd {
+		return t.AddProperty(property)
+	}
+	t.ReplaceProperty(property)
+
+	return nil
+}
+
+func patchPropertyAttribute(as *spec.ApiSpec, t *spec.TgTypeSpec, name, attribute string, p Patch) error {
+	property, exists := getProperty(t, name)
+	if !exists {
+		return errors.New(fmt.Sprintf("property %s doesn't exist", name))
+	}
+
+	switch attribute {
+	case "description":
+		return patchDescription(property, p)
+	case "types":
+		definitions, err := patchDataTypes(as, property.GetDataTypes(), p)
+		if err != nil {
+			return err
+		}
+
+		property.ClearDataTypes()
+		return property.AddDataType(as.DeclareUnionDataType(definitions...))
+	case "enum":
+		enum, err := patchEnum(property.GetEnum(), p)
+		if err != nil {
+			return err
+		}
+
+		property.SetEnum(enum)
+		return nil
+	case "optional":
+		var optional bool
+		if err := decodeReplacement(p, &optional); err != nil {
+			return err
+		}
+
+		property.SetOptional(optional)
+		return nil
+	}
+
+	return errors.New("unknown attribute " + attribute)
+}
+
+func patchMethod(as *spec.ApiSpec, name string, p Patch) error {
+	_, exists := as.GetMethod(name)
+	if err := checkTarget(p.Op, exists, "method "+name); err != nil {
+		return err
+	}
+
+	if p.Op == OpRemove {
+		as.RemoveMethod(name)
+		return nil
+	}
+
+	var value export_to_json.TgMethod
+	if err := decodeValue(p, &value); err != nil {
+		return err
+	}
+	if err := checkName(&value.Name, name); err != nil {
+		return err
+	}
+
+	m, err := datasource_json.NewTgMethod(as, value)
+	if err != nil {
+		return err
+	}
+
+	return as.AddMethod(m)
+}
+
+// patchReturnTypes keeps the conditional returns which data types are still returned.
+func patchReturnTypes(as *spec.ApiSpec, m *spec.TgMethodSpec, p Patch) error {
+	definitions, err := patchDataTypes(as, m.GetReturnTypes(), p)
+	if err != nil {
+		return err
+	}
+
+	conditionalReturns := m.GetConditionalReturns()
+	m.ClearReturnTypes()
+
+	returnType := as.DeclareUnionDataType(definitions...)
+	m.AddReturnType(returnType)
+
+	for _, cr := range conditionalReturns {
+		if containsDefinition(spec.FlattenDataTypes([]spec.DataTypeDefinition{returnType}), cr.GetDataType().GetDefinition()) {
+			m.AddConditionalReturn(cr)
+		}
+	}
+
+	return nil
+}
+
+func patchArgument(as *spec.ApiSpec, m *spec.TgMethodSpec, name string, p Patch) error {
+	_, exists := getArgument(m, name)
+	if err := checkTarget(p.Op, exists, "argument "+name); err != nil {
+		return err
+	}
+
+	if p.Op == OpRemove {
+		m.RemoveArgument(name)
+		return nil
+	}
+
+	var value export_to_json.TgMethodArgument
+	if err := decodeValue(p, &value); err != nil {
+		return err
+	}
+	if err := checkName(&value.Name, name); err != nil {
+		return err
+	}
+
+	argument, err := datasource_json.NewTgMethodArgument(as, value)
+	if err != nil {
+		return err
+	}
+
+	if p.Op == OpAdd {
+		return m.AddArgument(argument)
+	}
+	m.ReplaceArgument(argument)
+
+	return nil
+}
+
+func patchArgumentAttribute(as *spec.ApiSpec, m *spec.TgMethodSpec, name, attribute string, p Patch) error {
+	argument, exists := getArgument(m, name)
+	if !exists {
+		return errors.New(fmt.Sprintf("argument %s doesn't exist", name))
+	}
+
+	switch attribute {
+	case "description":
+		return patchDescription(argument, p)
+	case "types":
+		definitions, err := patchDataTypes(as, argument.GetDataTypes(), p)
+		if err != nil {
+			return err
+		}
+
+		// The default value is dropped if it doesn't match new data types.
+		defaultValue := argument.GetDefault()
+		argument.ClearDataTypes()
+		if err := argument.AddDataType(as.DeclareUnionDataType(definitions...)); err != nil {
+			return err
+		}
+		argument.SetDefault(defaultValue)
+
+		return nil
+	case "enum":
+		enum, err := patchEnum(argument.GetEnum(), p)
+		if err != nil {
+			return err
+		}
+
+		argument.SetEnum(enum)
+		return nil
+	case "required":
+		var required bool
+		if err := decodeReplacement(p, &required); err != nil {
+			return err
+		}
+
+		argument.SetRequired(required)
+		return nil
+	}
+
+	return errors.New("unknown attribute " + attribute)
+}
+
+type describable interface {
+	SetDescription(description string)
+}
+
+func patchDescription(element describable, p Patch) error {
+	var description string
+	if err := decodeReplacement(p, &description); err != nil {
+		return err
+	}
+
+	element.SetDescription(description)
+
+	return nil
+}
+
+// patchDataTypes returns definitions of the members of the new union.
+func patchDataTypes(as *spec.ApiSpec, current []spec.DataTypeDefinition, p Patch) ([]string, error) {
+	var values []string
+	if err := decodeValue(p, &values); err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, errors.New("value must list data types")
+	}
+
+	// Values are declared to get their canonical definitions, unused ones aren't reported by the spec check.
+	for i, v := range values {
+		values[i] = as.DeclareDataType(v).GetDefinition()
+	}
+
+	var definitions []string
+	for _, dt := range spec.FlattenDataTypes(current) {
+		definitions = append(definitions, dt.GetDefinition())
+	}
+
+	switch p.Op {
+	case OpAdd:
+		return append(definitions, values...), nil
+	case OpReplace:
+		return values, nil
+	}
+
+	var rest []string
+	for _, d := range definitions {
+		if !utils.Contains(values, d) {
+			rest = append(rest, d)
+		}
+	}
+
+	for _, v := range values {
+		if !utils.Contains(definitions, v) {
+			return nil, errors.New(fmt.Sprintf("data type %s doesn't exist", v))
+		}
+	}
+
+	if len(rest) == 0 {
+		return nil, errors.New("can't remove all the data types")
+	}
+
+	return rest, nil
+}
+
+// patchEnum removes the whole list of values on OpRemove.
+func patchEnum(current []string, p Patch) ([]string, error) {
+	if p.Op == OpRemove {
+		if len(current) == 0 {
+			return nil, errors.New("enum doesn't exist")
+		}
+
+		return nil, nil
+	}
+
+	var values []string
+	if err := decodeValue(p, &values); err != nil {
+		return nil, err
+	}
+
+	if p.Op == OpAdd {
+		return append(append([]string{}, current...), values...), nil
+	}
+
+	return values, nil
+}
+
+func checkTarget(op Operation, exists bool, target string) error {
+	if op == OpAdd && exists {
+		return errors.New(target + " already exists")
+	}
+
+	if op != OpAdd && !exists {
+		return errors.New(target + " doesn't exist")
+	}
+
+	return nil
+}
+
+// checkName fills the missing name of the value from the path.
+func checkName(valueName *string, name string) error {
+	if *valueName == "" {
+		*valueName = name
+	}
+
+	if *valueName != name {
+		return errors.New(fmt.Sprintf("name %s of the value doesn't match the path", *valueName))
+	}
+
+	return nil
+}
+
+func decodeValue(p Patch, v interface{}) error {
+	if len(p.Value) == 0 {
+		return errors.New("value is required")
+	}
+
+	if err := json.Unmarshal(p.Value, v); err != nil {
+		return errors.New("invalid value: " + err.Error())
+	}
+
+	return nil
+}
+
+// decodeReplacement decodes the value of the attribute which can only be replaced.
+func decodeReplacement(p Patch, v interface{}) error {
+	if p.Op != OpReplace {
+		return errors.New(fmt.Sprintf("operation %s isn't supported by the target", p.Op))
+	}
+
+	return decodeValue(p, v)
+}
+
+func getTypes(as *spec.ApiSpec, names []string, relative string) ([]*spec.TgTypeSpec, error) {
+	var types []*spec.TgTypeSpec
+	for _, n := range names {
+		if n == relative {
+			return nil, errors.New(fmt.Sprintf("type %s can't be related to itself", n))
+		}
+
+		t, exists := as.GetType(n)
+		if !exists {
+			return nil, errors.New(fmt.Sprintf("related type %s doesn't exist", n))
+		}
+		types = append(types, t)
+	}
+
+	return types, nil
+}
+
+func getProperty(t *spec.TgTypeSpec, name string) (*spec.TgTypeSpecProperty, bool) {
+	for _, p := range t.GetProperties() {
+		if p.GetName() == name {
+			return p, true
+		}
+	}
+
+	return nil, false
+}
+
+func getArgument(m *spec.TgMethodSpec, name string) (*spec.TgMethodSpecArgument, bool) {
+	for _, a := range m.GetArguments() {
+		if a.GetName() == name {
+			return a, true
+		}
+	}
+
+	return nil, false
+}
+
+func containsDefinition(dataTypes []spec.DataTypeDefinition, definition string) bool {
+	for _, dt := range dataTypes {
+		if dt.GetDefinition() == definition {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,126 @@
+package export_to_json
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/changelog"
+)
+
+type ChangelogData struct {
+	Releases []ChangelogRelease `json:"releases"`
+}
+
+type ChangelogRelease struct {
+	Version string           `json:"version,omitempty"`
+	Date    string           `json:"date"`
+	Link    string           `json:"link"`
+	Entries []ChangelogEntry `json:"entries,omitempty"`
+}
+
+type ChangelogEntry struct {
+	Text       string               `json:"text"`
+	References []ChangelogReference `json:"references,omitempty"`
+	Entries    []ChangelogEntry     `json:"entries,omitempty"`
+}
+
+type ChangelogReference struct {
+	Text string `json:"text"`
+	Link string `json:"link"`
+	Kind string `json:"kind,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type ChangelogExporter struct {
+	data *ChangelogData
+}
+
+// Export saves `changelog.json` and `changelog.min.json` if the filename is the directory.
+func (ce ChangelogExporter) Export(filename string) error {
+	if ce.data == nil {
+		return errors.New("nothing to export")
+	}
+
+	return exportData(filename, "changelog", ce.data)
+}
+
+// exportData saves the indented and the minified files if the filename has no `.json` suffix.
+// The name is the name of files in case the filename is the directory.
+func exportData(filename, name string, data interface{}) error {
+	outPath, err := filepath.Abs(strings.TrimSpace(filename))
+	if err != nil {
+		return err
+	}
+
+	fileInfo, err := os.Stat(outPath)
+	if err == nil && fileInfo.IsDir() {
+		outPath += "/" + name
+	}
+
+	var paths []string
+	if !strings.HasSuffix(outPath, ".json") {
+		paths = append(paths, outPath+".json", outPath+".min.json")
+	} else {
+		paths = append(paths, outPath)
+	}
+
+	for _, path := range paths {
+		var content []byte
+		if strings.HasSuffix(path, ".min.json") {
+			content, err = json.Marshal(data)
+		} else {
+			content, err = json.MarshalIndent(data, "", "    ")
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("saving: " + path)
+		if err := ioutil.WriteFile(path, content, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func NewChangelogExporter(cl *changelog.Changelog) (*ChangelogExporter, error) {
+	if cl == nil || len(cl.GetReleases()) == 0 {
+		return nil, errors.New("changelog is empty")
+	}
+
+	data := &ChangelogData{}
+	for _, r := range cl.GetReleases() {
+		data.Releases = append(data.Releases, ChangelogRelease{
+			Version: r.GetVersion(),
+			Date:    r.GetDate(),
+			Link:    r.GetLink(),
+			Entries: getChangelogEntries(r.GetEntries()),
+		})
+	}
+
+	return &ChangelogExporter{data}, nil
+}
+
+func getChangelogEntries(entries []*changelog.Entry) []ChangelogEntry {
+	var result []ChangelogEntry
+	for _, e := range entries {
+		entry := ChangelogEntry{Text: e.GetText(), Entries: getChangelogEntries(e.GetEntries())}
+		for _, r := range e.GetReferences() {
+			entry.References = append(entry.References, ChangelogReference{
+				Text: r.GetText(),
+				Link: r.GetLink(),
+				Kind: string(r.GetKind()),
+				Name: r.GetName(),
+			})
+		}
+		result = append(result, entry)
+	}
+
+	return result
+}
@@ -0,0 +1,97 @@
+package export_to_json
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/changelog"
+)
+
+type changelogFunc func(cl *changelog.Changelog) error
+
+func (f changelogFunc) FillChangelog(cl *changelog.Changelog) error {
+	return f(cl)
+}
+
+func TestChangelogExporter(t *testing.T) {
+	cl, err := changelog.NewChangelog(changelogFunc(func(cl *changelog.Changelog) error {
+		release, _ := changelog.NewRelease("December 29, 2023", "https://core.telegram.org/bots/api-changelog#december-29-2023")
+		release.SetVersion("7.0")
+		entry, _ := changelog.NewEntry("Reactions")
+		nested, _ := changelog.NewEntry("Added the class ReactionType.")
+		reference, _ := changelog.NewReference("ReactionType", "https://core.telegram.org/bots/api#reactiontype")
+		nested.AddReference(reference)
+		entry.AddEntry(nested)
+		release.AddEntry(entry)
+		cl.AddRelease(release)
+
+		launch, _ := changelog.NewRelease("June 24, 2015", "https://core.telegram.org/bots/api-changelog#june-24-2015")
+
+		return cl.AddRelease(launch)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ce, err := NewChangelogExporter(cl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := ce.Export(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	want := ChangelogData{Releases: []ChangelogRelease{
+		{
+			Version: "7.0",
+			Date:    "December 29, 2023",
+			Link:    "https://core.telegram.org/bots/api-changelog#december-29-2023",
+			Entries: []ChangelogEntry{{
+				Text: "Reactions",
+				Entries: []ChangelogEntry{{
+					Text:       "Added the class ReactionType.",
+					References: []ChangelogReference{{Text: "ReactionType", Link: "https://core.telegram.org/bots/api#reactiontype"}},
+				}},
+			}},
+		},
+		{Date: "June 24, 2015", Link: "https://core.telegram.org/bots/api-changelog#june-24-2015"},
+	}}
+
+	for _, name := range []string{"changelog.json", "changelog.min.json"} {
+		t.Run(name, func(t *testing.T) {
+			content, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got ChangelogData
+			if err := json.Unmarshal(content, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestChangelogExporterRejectsEmptyChangelog(t *testing.T) {
+	cl, err := changelog.NewChangelog(changelogFunc(func(cl *changelog.Changelog) error {
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewChangelogExporter(cl); err == nil {
+		t.Error("expected the error for the empty changelog")
+	}
+	if _, err := NewChangelogExporter(nil); err == nil {
+		t.Error("expected the error for the nil changelog")
+	}
+}
@@ -78,6 +78,11 @@ func (je JsonExporter) Export(filename string) error {
 }
 
 func NewApiSpecExporter(as spec.ApiSpec) (*JsonExporter, error) {
+	return NewOrderedApiSpecExporter(as, spec.OrderAlphabetical)
+}
+
+// NewOrderedApiSpecExporter creates an exporter which lists properties and arguments according to the provided ordering.
+func NewOrderedApiSpecExporter(as spec.ApiSpec, ordering spec.Ordering) (*JsonExporter, error) {
 	if err := as.SelfCheck(); err != nil {
 		return nil, errors.New("invalid spec: " + err.Error())
 	}
@@ -92,18 +97,36 @@ func NewApiSpecExporter(as spec.ApiSpec) (*JsonExporter, error) {
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		fillTypes(data, as.GetTypes())
+		fillTypes(data, as.GetTypes(), ordering)
 	}()
 	go func() {
 		defer wg.Done()
-		fillMethods(data, as.GetMethods())
+		fillMethods(data, as.GetMethods(), ordering)
 	}()
 	wg.Wait()
 
+	fillAliases(data, as.GetAliases())
+
 	return &JsonExporter{as, data}, nil
 }
 
-func fillTypes(data *JsonData, types map[string]*spec.TgTypeSpec) {
+func fillAliases(data *JsonData, aliases map[string]*spec.DataTypeAlias) {
+	if len(aliases) == 0 {
+		return
+	}
+
+	data.Aliases = make(map[string]DataTypeAlias)
+	for _, a := range aliases {
+		var types []string
+		for _, dt := range a.GetDataType().GetMemberDataTypes() {
+			types = append(types, dt.GetDefinition())
+		}
+
+		data.Aliases[a.GetName()] = DataTypeAlias{Name: a.GetName(), Types: types}
+	}
+}
+
+func fillTypes(data *JsonData, types map[string]*spec.TgTypeSpec, ordering spec.Ordering) {
 	data.Types = make(map[string]TgType)
 
 	for _, t := range types {
@@ -112,14 +135,18 @@ func fillTypes(data *JsonData, types map[string]*spec.TgTypeSpec) {
 			Name:        t.GetName(),
 			Link:        t.GetLink(),
 			Description: t.GetDescription(),
-			Properties:  getProperties(t.GetProperties()),
+			Properties:  getProperties(t.GetOrderedProperties(ordering)),
+			Order:       t.GetOrder(),
 		}
 
-		parent := t.GetParent()
-		if parent != nil {
-			v := NilableString(parent.GetName())
-			tgType.Parent = &v
+		var parents []string
+		for _, p := range t.GetParents() {
+			parents = append(parents, p.GetName())
+		}
+		if len(parents) > 1 {
+			sort.Strings(parents)
 		}
+		tgType.Parents = parents
 
 		var children []string
 		for _, c := range t.GetChildren() {
@@ -130,6 +157,16 @@ func fillTypes(data *JsonData, types map[string]*spec.TgTypeSpec) {
 		}
 		tgType.Children = children
 
+		if d := t.GetDiscriminator(); d != nil {
+			discriminator := &TgTypeDiscriminator{PropertyName: d.GetPropertyName(), Mapping: make(map[string]string)}
+			for value, child := range d.GetMapping() {
+				discriminator.Mapping[value] = child.GetName()
+			}
+			tgType.Discriminator = discriminator
+		}
+
+		tgType.Since, tgType.LastChanged, tgType.Until = versions(t.GetHistory())
+
 		data.Types[t.GetName()] = tgType
 	}
 }
@@ -141,10 +178,12 @@ func getProperties(props []*spec.TgTypeSpecProperty) []TgTypeProperty {
 			Name:        p.GetName(),
 			Description: p.GetDescription(),
 			Optional:    p.IsOptional(),
+			Enum:        p.GetEnum(),
+			Order:       p.GetOrder(),
 		}
 
 		var types []string
-		for _, dt := range p.GetDataTypes() {
+		for _, dt := range spec.FlattenDataTypes(p.GetDataTypes()) {
 			types = append(types, dt.GetDefinition())
 		}
 		if len(types) > 1 {
@@ -152,25 +191,25 @@ func getProperties(props []*spec.TgTypeSpecProperty) []TgTypeProperty {
 		}
 		ttp.Types = types
 
+		if c := p.GetConstraints(); c != nil {
+			ttp.MinLength, ttp.MaxLength, ttp.Minimum, ttp.Maximum = c.MinLength, c.MaxLength, c.Minimum, c.Maximum
+		}
+
 		value := p.GetPredefinedValue()
 		if value != nil {
 			v := NilableString(*value)
 			ttp.PredefinedValue = &v
 		}
 
-		properties = append(properties, ttp)
-	}
+		ttp.Since, ttp.LastChanged, ttp.Until = versions(p.GetHistory())
 
-	if len(properties) > 1 {
-		sort.SliceStable(properties, func(i, j int) bool {
-			return properties[i].Name < properties[j].Name
-		})
+		properties = append(properties, ttp)
 	}
 
 	return properties
 }
 
-func fillMethods(data *JsonData, methods map[string]*spec.TgMethodSpec) {
+func fillMethods(data *JsonData, methods map[string]*spec.TgMethodSpec, ordering spec.Ordering) {
 	data.Methods = make(map[string]TgMethod)
 
 	for _, m := range methods {
@@ -179,11 +218,12 @@ func fillMethods(data *JsonData, methods map[string]*spec.TgMethodSpec) {
 			Name:        m.GetName(),
 			Link:        m.GetLink(),
 			Description: m.GetDescription(),
-			Arguments:   getArguments(m.GetArguments()),
+			Arguments:   getArguments(m.GetOrderedArguments(ordering)),
+			Order:       m.GetOrder(),
 		}
 
 		var returns []string
-		for _, r := range m.GetReturnTypes() {
+		for _, r := range spec.FlattenDataTypes(m.GetReturnTypes()) {
 			returns = append(returns, r.GetDefinition())
 		}
 		if len(returns) > 1 {
@@ -191,6 +231,24 @@ func fillMethods(data *JsonData, methods map[string]*spec.TgMethodSpec) {
 		}
 		tgMethod.Returns = returns
 
+		for _, cr := range m.GetConditionalReturns() {
+			tgMethod.ConditionalReturns = append(tgMethod.ConditionalReturns, TgMethodConditionalReturn{
+				Type:      cr.GetDataType().GetDefinition(),
+				Condition: cr.GetCondition(),
+				Arguments: cr.GetArguments(),
+			})
+		}
+
+		for _, g := range m.GetArgumentGroups() {
+			tgMethod.ArgumentGroups = append(tgMethod.ArgumentGroups, TgMethodArgumentGroup{
+				Kind:      string(g.GetKind()),
+				Arguments: g.GetArguments(),
+				DependsOn: g.GetDependsOn(),
+			})
+		}
+
+		tgMethod.Since, tgMethod.LastChanged, tgMethod.Until = versions(m.GetHistory())
+
 		data.Methods[m.GetName()] = tgMethod
 	}
 }
@@ -202,10 +260,13 @@ func getArguments(args []*spec.TgMethodSpecArgument) []TgMethodArgument {
 			Name:        a.GetName(),
 			Description: a.GetDescription(),
 			Required:    a.IsRequired(),
+			Enum:        a.GetEnum(),
+			Default:     a.GetDefault(),
+			Order:       a.GetOrder(),
 		}
 
 		var types []string
-		for _, dt := range a.GetDataTypes() {
+		for _, dt := range spec.FlattenDataTypes(a.GetDataTypes()) {
 			types = append(types, dt.GetDefinition())
 		}
 		if len(types) > 1 {
@@ -213,14 +274,22 @@ func getArguments(args []*spec.TgMethodSpecArgument) []TgMethodArgument {
 		}
 		tma.Types = types
 
+		if c := a.GetConstraints(); c != nil {
+			tma.MinLength, tma.MaxLength, tma.Minimum, tma.Maximum = c.MinLength, c.MaxLength, c.Minimum, c.Maximum
+		}
+
+		tma.Since, tma.LastChanged, tma.Until = versions(a.GetHistory())
+
 		arguments = append(arguments, tma)
 	}
 
-	if len(arguments) > 1 {
-		sort.SliceStable(arguments, func(i, j int) bool {
-			return arguments[i].Name < arguments[j].Name
-		})
+	return arguments
+}
+
+func versions(history *spec.VersionHistory) (string, string, string) {
+	if history == nil {
+		return "", "", ""
 	}
 
-	return arguments
+	return history.Since, history.LastChanged, history.Until
 }
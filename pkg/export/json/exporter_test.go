@@ -0,0 +1,75 @@
+package export_to_json
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+func TestExporterOrdering(t *testing.T) {
+	as := spectest.New(t, func(as *spec.ApiSpec) error {
+		spectest.SetRelease(as, "7.0")
+		spectest.AddType(as, "Chat", "id int64")
+		message := spectest.AddType(as, "Message", "message_id int32", "date int32", "chat Chat", "text string")
+		// The property without a position, e.g. added by the supplement, goes last.
+		for i, p := range message.GetProperties()[:3] {
+			p.SetOrder(i + 1)
+		}
+		sendMessage := spectest.AddMethod(as, "sendMessage", "Message", "chat_id int64|string", "text string", "parse_mode string")
+		for i, a := range sendMessage.GetArguments()[:2] {
+			a.SetOrder(i + 1)
+		}
+
+		return nil
+	})
+
+	tests := []struct {
+		ordering   spec.Ordering
+		properties string
+		arguments  string
+	}{
+		{spec.OrderAlphabetical, "chat,date,message_id,text", "chat_id,parse_mode,text"},
+		{spec.OrderDocument, "message_id,date,chat,text", "chat_id,text,parse_mode"},
+	}
+
+	for _, tt := range tests {
+		je, err := NewOrderedApiSpecExporter(*as, tt.ordering)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		file := filepath.Join(t.TempDir(), "spec.json")
+		if err := je.Export(file); err != nil {
+			t.Fatal(err)
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var data JsonData
+		if err := json.Unmarshal(content, &data); err != nil {
+			t.Fatal(err)
+		}
+
+		var properties, arguments []string
+		for _, p := range data.Types["Message"].Properties {
+			properties = append(properties, p.Name)
+		}
+		for _, a := range data.Methods["sendMessage"].Arguments {
+			arguments = append(arguments, a.Name)
+		}
+
+		if got := strings.Join(properties, ","); got != tt.properties {
+			t.Errorf("ordering %d: got properties %s, want %s", tt.ordering, got, tt.properties)
+		}
+		if got := strings.Join(arguments, ","); got != tt.arguments {
+			t.Errorf("ordering %d: got arguments %s, want %s", tt.ordering, got, tt.arguments)
+		}
+	}
+}
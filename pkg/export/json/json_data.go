@@ -1,21 +1,33 @@
 package export_to_json
 
 type JsonData struct {
-	Version     string              `json:"version"`
-	ReleaseDate string              `json:"releaseDate"`
-	Link        string              `json:"link"`
-	Types       map[string]TgType   `json:"types"`
-	Methods     map[string]TgMethod `json:"methods"`
+	Version     string                   `json:"version"`
+	ReleaseDate string                   `json:"releaseDate"`
+	Link        string                   `json:"link"`
+	Types       map[string]TgType        `json:"types"`
+	Methods     map[string]TgMethod      `json:"methods"`
+	Aliases     map[string]DataTypeAlias `json:"aliases,omitempty"`
 }
 
 type TgType struct {
-	Category    string           `json:"category"`
-	Name        string           `json:"name"`
-	Link        string           `json:"link"`
-	Description string           `json:"description"`
-	Parent      *NilableString   `json:"parent,omitempty"`
-	Children    []string         `json:"children,omitempty"`
-	Properties  []TgTypeProperty `json:"properties,omitempty"`
+	Category      string               `json:"category"`
+	Name          string               `json:"name"`
+	Link          string               `json:"link"`
+	Description   string               `json:"description"`
+	Parents       []string             `json:"parents,omitempty"`
+	Parent        *NilableString       `json:"parent,omitempty"` // Deprecated: only read from files of old versions, use Parents.
+	Children      []string             `json:"children,omitempty"`
+	Discriminator *TgTypeDiscriminator `json:"discriminator,omitempty"` // Derived from children, so it's ignored on reading.
+	Properties    []TgTypeProperty     `json:"properties,omitempty"`
+	Order         int                  `json:"order,omitempty"`
+	Since         string               `json:"since,omitempty"`
+	LastChanged   string               `json:"lastChanged,omitempty"`
+	Until         string               `json:"until,omitempty"`
+}
+
+type TgTypeDiscriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping"`
 }
 
 type TgTypeProperty struct {
@@ -24,22 +36,64 @@ type TgTypeProperty struct {
 	Types           []string       `json:"types"`
 	Optional        bool           `json:"optional"`
 	PredefinedValue *NilableString `json:"default,omitempty"`
+	Enum            []string       `json:"enum,omitempty"`
+	MinLength       *int           `json:"minLength,omitempty"`
+	MaxLength       *int           `json:"maxLength,omitempty"`
+	Minimum         *float64       `json:"minimum,omitempty"`
+	Maximum         *float64       `json:"maximum,omitempty"`
+	Order           int            `json:"order,omitempty"`
+	Since           string         `json:"since,omitempty"`
+	LastChanged     string         `json:"lastChanged,omitempty"`
+	Until           string         `json:"until,omitempty"`
 }
 
 type TgMethod struct {
-	Category    string             `json:"category"`
-	Name        string             `json:"name"`
-	Link        string             `json:"link"`
-	Description string             `json:"description"`
-	Arguments   []TgMethodArgument `json:"arguments,omitempty"`
-	Returns     []string           `json:"returns"`
+	Category           string                      `json:"category"`
+	Name               string                      `json:"name"`
+	Link               string                      `json:"link"`
+	Description        string                      `json:"description"`
+	Arguments          []TgMethodArgument          `json:"arguments,omitempty"`
+	ArgumentGroups     []TgMethodArgumentGroup     `json:"argumentGroups,omitempty"`
+	Returns            []string                    `json:"returns"`
+	ConditionalReturns []TgMethodConditionalReturn `json:"conditionalReturns,omitempty"`
+	Order              int                         `json:"order,omitempty"`
+	Since              string                      `json:"since,omitempty"`
+	LastChanged        string                      `json:"lastChanged,omitempty"`
+	Until              string                      `json:"until,omitempty"`
 }
 
 type TgMethodArgument struct {
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	Required    bool     `json:"required"`
-	Types       []string `json:"types"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Required    bool        `json:"required"`
+	Types       []string    `json:"types"`
+	Enum        []string    `json:"enum,omitempty"`
+	MinLength   *int        `json:"minLength,omitempty"`
+	MaxLength   *int        `json:"maxLength,omitempty"`
+	Minimum     *float64    `json:"minimum,omitempty"`
+	Maximum     *float64    `json:"maximum,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+	Order       int         `json:"order,omitempty"`
+	Since       string      `json:"since,omitempty"`
+	LastChanged string      `json:"lastChanged,omitempty"`
+	Until       string      `json:"until,omitempty"`
+}
+
+type TgMethodArgumentGroup struct {
+	Kind      string   `json:"kind"`
+	Arguments []string `json:"arguments"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+type TgMethodConditionalReturn struct {
+	Type      string   `json:"type"`
+	Condition string   `json:"condition"`
+	Arguments []string `json:"arguments,omitempty"`
+}
+
+type DataTypeAlias struct {
+	Name  string   `json:"name"`
+	Types []string `json:"types"`
 }
 
 type NilableString string
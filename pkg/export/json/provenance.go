@@ -0,0 +1,64 @@
+package export_to_json
+
+import (
+	"errors"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+// ProvenanceData maps paths of elements, e.g. `types.Message.properties.chat`, to places of the page they were scraped from.
+type ProvenanceData struct {
+	Version  string                `json:"version"`
+	Elements map[string]Provenance `json:"elements"`
+}
+
+type Provenance struct {
+	Anchor   string   `json:"anchor"`
+	Heading  string   `json:"heading"`
+	Position int      `json:"position"`
+	Row      int      `json:"row,omitempty"`
+	Cells    []string `json:"cells,omitempty"`
+}
+
+type ProvenanceExporter struct {
+	data *ProvenanceData
+}
+
+// Export saves `provenance.json` and `provenance.min.json` if the filename is the directory.
+func (pe ProvenanceExporter) Export(filename string) error {
+	if pe.data == nil {
+		return errors.New("nothing to export")
+	}
+
+	return exportData(filename, "provenance", pe.data)
+}
+
+// NewProvenanceExporter skips elements without provenance, e.g. the ones filled by supplements.
+func NewProvenanceExporter(as spec.ApiSpec) (*ProvenanceExporter, error) {
+	data := &ProvenanceData{Version: as.GetVersion(), Elements: make(map[string]Provenance)}
+	add := func(path string, p *spec.Provenance) {
+		if p != nil {
+			data.Elements[path] = Provenance{Anchor: p.Anchor, Heading: p.Heading, Position: p.Position, Row: p.Row, Cells: p.Cells}
+		}
+	}
+
+	for _, t := range as.GetTypes() {
+		add("types."+t.GetName(), t.GetProvenance())
+		for _, p := range t.GetProperties() {
+			add("types."+t.GetName()+".properties."+p.GetName(), p.GetProvenance())
+		}
+	}
+
+	for _, m := range as.GetMethods() {
+		add("methods."+m.GetName(), m.GetProvenance())
+		for _, a := range m.GetArguments() {
+			add("methods."+m.GetName()+".arguments."+a.GetName(), a.GetProvenance())
+		}
+	}
+
+	if len(data.Elements) == 0 {
+		return nil, errors.New("no provenance recorded, the spec isn't scraped")
+	}
+
+	return &ProvenanceExporter{data}, nil
+}
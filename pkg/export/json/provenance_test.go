@@ -0,0 +1,68 @@
+package export_to_json
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+func TestProvenanceExporter(t *testing.T) {
+	cells := []string{"chat_id", "Integer or String", "Yes", "Unique identifier for the target chat"}
+	as := spectest.New(t, func(as *spec.ApiSpec) error {
+		as.SetVersion("7.0")
+		spectest.AddType(as, "Chat", "id int64").SetProvenance(&spec.Provenance{Anchor: "chat", Heading: "Chat", Position: 5})
+		// The type filled by the supplement has no provenance.
+		spectest.AddType(as, "ChatId")
+		m := spectest.AddMethod(as, "sendMessage", "Chat", "chat_id int64|string")
+		m.SetProvenance(&spec.Provenance{Anchor: "sendmessage", Heading: "sendMessage", Position: 11})
+		m.GetArguments()[0].SetProvenance(&spec.Provenance{Anchor: "sendmessage", Heading: "sendMessage", Position: 13, Row: 1, Cells: cells})
+
+		return nil
+	})
+
+	pe, err := NewProvenanceExporter(*as)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(t.TempDir(), "provenance.json")
+	if err := pe.Export(file); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got ProvenanceData
+	if err := json.Unmarshal(content, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := ProvenanceData{Version: "7.0", Elements: map[string]Provenance{
+		"types.Chat":                            {Anchor: "chat", Heading: "Chat", Position: 5},
+		"methods.sendMessage":                   {Anchor: "sendmessage", Heading: "sendMessage", Position: 11},
+		"methods.sendMessage.arguments.chat_id": {Anchor: "sendmessage", Heading: "sendMessage", Position: 13, Row: 1, Cells: cells},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestProvenanceExporterRejectsSpecWithoutProvenance(t *testing.T) {
+	as := spectest.New(t, func(as *spec.ApiSpec) error {
+		spectest.AddType(as, "Chat", "id int64")
+
+		return nil
+	})
+
+	if _, err := NewProvenanceExporter(*as); err == nil {
+		t.Error("expected the error for the spec which isn't scraped")
+	}
+}
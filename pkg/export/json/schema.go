@@ -47,6 +47,18 @@ const Schema string = `
 					"$ref": "#/definitions/Method"
 				}
 			}
+		},
+		"aliases": {
+			"title": "Named data types",
+			"description": "Unions of data types which recur across the spec, e.g. 'ChatId' for chat identifiers. Properties and arguments still list all the data types of the union.",
+			"type": "object",
+			"additionalProperties": false,
+			"minProperties": 1,
+			"patternProperties": {
+				"^[A-Z][A-Za-z0-9_]+$": {
+					"$ref": "#/definitions/DataTypeAlias"
+				}
+			}
 		}
 	},
 	"definitions": {
@@ -86,8 +98,16 @@ const Schema string = `
 						"$ref": "#/definitions/TypeProperty"
 					}
 				},
+				"parents": {
+					"description": "List of Telegram types names that can be parents of the provided type.",
+					"type": "array",
+					"minItems": 1,
+					"items": {
+						"$ref": "#/definitions/nonEmptyString"
+					}
+				},
 				"parent": {
-					"description": "The name of the Telegram type that can be a parent of provided.",
+					"description": "Deprecated, use 'parents'. The name of the Telegram type that can be a parent of provided.",
 					"$ref": "#/definitions/nonEmptyString"
 				},
 				"children": {
@@ -97,6 +117,50 @@ const Schema string = `
 					"items": {
 						"$ref": "#/definitions/nonEmptyString"
 					}
+				},
+				"discriminator": {
+					"description": "The property which value tells what child the object of the provided polymorphic type is. It's derived from the predefined values of properties of children.",
+					"$ref": "#/definitions/TypeDiscriminator"
+				},
+				"since": {
+					"description": "Bot API version which introduced the provided Telegram type.",
+					"$ref": "#/definitions/nonEmptyString"
+				},
+				"lastChanged": {
+					"description": "Bot API version which changed the provided Telegram type for the last time.",
+					"$ref": "#/definitions/nonEmptyString"
+				},
+				"until": {
+					"description": "Bot API version which removed the provided Telegram type.",
+					"$ref": "#/definitions/nonEmptyString"
+				},
+				"order": {
+					"description": "Position of the provided Telegram type in the official doc.",
+					"$ref": "#/definitions/order"
+				}
+			}
+		},
+		"TypeDiscriminator": {
+			"title": "Type discriminator",
+			"description": "This object describes how to tell the children of the polymorphic Telegram type apart.",
+			"type": "object",
+			"additionalProperties": false,
+			"required": [
+				"propertyName",
+				"mapping"
+			],
+			"properties": {
+				"propertyName": {
+					"description": "Name of the property which has a predefined value in every child.",
+					"$ref": "#/definitions/nonEmptyString"
+				},
+				"mapping": {
+					"description": "Names of the children by values of the property.",
+					"type": "object",
+					"minProperties": 1,
+					"additionalProperties": {
+						"$ref": "#/definitions/nonEmptyString"
+					}
 				}
 			}
 		},
@@ -131,6 +195,42 @@ const Schema string = `
 				"default": {
 					"description": "Default value of the provided property (field).",
 					"$ref": "#/definitions/nonEmptyString"
+				},
+				"enum": {
+					"description": "List of values that provided property (field) can be.",
+					"$ref": "#/definitions/enum"
+				},
+				"minLength": {
+					"description": "Minimal length of the provided property (field) value.",
+					"$ref": "#/definitions/length"
+				},
+				"maxLength": {
+					"description": "Maximal length of the provided property (field) value.",
+					"$ref": "#/definitions/length"
+				},
+				"minimum": {
+					"description": "Minimal value of the provided property (field).",
+					"type": "number"
+				},
+				"maximum": {
+					"description": "Maximal value of the provided property (field).",
+					"type": "number"
+				},
+				"since": {
+					"description": "Bot API version which introduced the provided property (field).",
+					"$ref": "#/definitions/nonEmptyString"
+				},
+				"lastChanged": {
+					"description": "Bot API version which changed the provided property (field) for the last time.",
+					"$ref": "#/definitions/nonEmptyString"
+				},
+				"until": {
+					"description": "Bot API version which removed the provided property (field).",
+					"$ref": "#/definitions/nonEmptyString"
+				},
+				"order": {
+					"description": "Position of the provided property (field) in the official doc.",
+					"$ref": "#/definitions/order"
 				}
 			}
 		},
@@ -171,9 +271,41 @@ const Schema string = `
 						"$ref": "#/definitions/MethodArgument"
 					}
 				},
+				"argumentGroups": {
+					"description": "Rules which bind several arguments (parameters) of the provided Telegram method together.",
+					"type": "array",
+					"minItems": 1,
+					"items": {
+						"$ref": "#/definitions/MethodArgumentGroup"
+					}
+				},
 				"returns": {
 					"description": "List of data types that can be returned by provided Telegram method.",
 					"$ref": "#/definitions/dataTypes"
+				},
+				"conditionalReturns": {
+					"description": "Conditions under which the return types of the provided Telegram method are returned.",
+					"type": "array",
+					"minItems": 1,
+					"items": {
+						"$ref": "#/definitions/MethodConditionalReturn"
+					}
+				},
+				"since": {
+					"description": "Bot API version which introduced the provided Telegram method.",
+					"$ref": "#/definitions/nonEmptyString"
+				},
+				"lastChanged": {
+					"description": "Bot API version which changed the provided Telegram method for the last time.",
+					"$ref": "#/definitions/nonEmptyString"
+				},
+				"until": {
+					"description": "Bot API version which removed the provided Telegram method.",
+					"$ref": "#/definitions/nonEmptyString"
+				},
+				"order": {
+					"description": "Position of the provided Telegram method in the official doc.",
+					"$ref": "#/definitions/order"
 				}
 			}
 		},
@@ -204,6 +336,123 @@ const Schema string = `
 				"required": {
 					"description": "Describes if the provided argument (parameter) is required or not.",
 					"type": "boolean"
+				},
+				"enum": {
+					"description": "List of values that provided argument (parameter) can be.",
+					"$ref": "#/definitions/enum"
+				},
+				"minLength": {
+					"description": "Minimal length of the provided argument (parameter) value.",
+					"$ref": "#/definitions/length"
+				},
+				"maxLength": {
+					"description": "Maximal length of the provided argument (parameter) value.",
+					"$ref": "#/definitions/length"
+				},
+				"minimum": {
+					"description": "Minimal value of the provided argument (parameter).",
+					"type": "number"
+				},
+				"maximum": {
+					"description": "Maximal value of the provided argument (parameter).",
+					"type": "number"
+				},
+				"default": {
+					"description": "Value that is used by Telegram when the provided argument (parameter) is not passed.",
+					"type": ["string", "number", "boolean"]
+				},
+				"since": {
+					"description": "Bot API version which introduced the provided argument (parameter).",
+					"$ref": "#/definitions/nonEmptyString"
+				},
+				"lastChanged": {
+					"description": "Bot API version which changed the provided argument (parameter) for the last time.",
+					"$ref": "#/definitions/nonEmptyString"
+				},
+				"until": {
+					"description": "Bot API version which removed the provided argument (parameter).",
+					"$ref": "#/definitions/nonEmptyString"
+				},
+				"order": {
+					"description": "Position of the provided argument (parameter) in the official doc.",
+					"$ref": "#/definitions/order"
+				}
+			}
+		},
+		"MethodArgumentGroup": {
+			"title": "Method arguments group",
+			"description": "This object describes the rule which binds several arguments (parameters) of the provided Telegram method together.",
+			"type": "object",
+			"additionalProperties": false,
+			"required": [
+				"kind",
+				"arguments"
+			],
+			"properties": {
+				"kind": {
+					"description": "Kind of the rule. 'exactlyOneOf' - exactly one of the arguments must be passed. 'requiredIfAbsent' - the arguments are required unless all the 'dependsOn' arguments are passed. 'mutuallyExclusive' - at most one of the arguments can be passed.",
+					"type": "string",
+					"enum": [
+						"exactlyOneOf",
+						"requiredIfAbsent",
+						"mutuallyExclusive"
+					]
+				},
+				"arguments": {
+					"description": "Names of the arguments (parameters) in the group.",
+					"$ref": "#/definitions/argumentNames"
+				},
+				"dependsOn": {
+					"description": "Names of the arguments (parameters) which affect the group.",
+					"$ref": "#/definitions/argumentNames"
+				}
+			}
+		},
+		"MethodConditionalReturn": {
+			"title": "Method conditional return type",
+			"description": "This object describes the condition under which the data type is returned by the provided Telegram method.",
+			"type": "object",
+			"additionalProperties": false,
+			"required": [
+				"type",
+				"condition"
+			],
+			"properties": {
+				"type": {
+					"description": "Data type which is returned. It must be listed in the return types of the method.",
+					"$ref": "#/definitions/nonEmptyString"
+				},
+				"condition": {
+					"description": "The condition as it's written in the official doc, or 'otherwise' for the fallback data type.",
+					"$ref": "#/definitions/nonEmptyString"
+				},
+				"arguments": {
+					"description": "Names of the arguments (parameters) which decide the condition.",
+					"$ref": "#/definitions/argumentNames"
+				}
+			}
+		},
+		"DataTypeAlias": {
+			"title": "Data type alias",
+			"description": "This object describes the name of the union of data types.",
+			"type": "object",
+			"additionalProperties": false,
+			"required": [
+				"name",
+				"types"
+			],
+			"properties": {
+				"name": {
+					"description": "Name of the alias. It doesn't conflict with the names of the Telegram types.",
+					"$ref": "#/definitions/nonEmptyString"
+				},
+				"types": {
+					"description": "List of data types that the alias unites.",
+					"type": "array",
+					"minItems": 2,
+					"items": {
+						"$ref": "#/definitions/nonEmptyString"
+					}
 				}
 			}
 		},
@@ -215,11 +464,34 @@ const Schema string = `
             "type": "string",
 			"format": "uri"
         },
+		"order": {
+			"type": "integer",
+			"minimum": 1
+		},
+		"argumentNames": {
+			"type": "array",
+			"minItems": 1,
+			"items": {
+				"$ref": "#/definitions/nonEmptyString"
+			}
+		},
+		"length": {
+			"type": "integer",
+			"minimum": 0
+		},
+		"enum": {
+			"type": "array",
+			"minItems": 1,
+			"uniqueItems": true,
+			"items": {
+				"$ref": "#/definitions/nonEmptyString"
+			}
+		},
 		"dataTypes": {
 			"type": "array",
 			"minItems": 1,
 			"items": {
-				"description": "Data type name. It can be as one of the available data type definitions, either as the name of the Telegram type.",
+				"description": "Data type name. It can be as one of the available data type definitions (string, int32, int64, float, boolean, true - a boolean which can be only true), either as the name of the Telegram type.",
 				"$ref": "#/definitions/nonEmptyString"
 			}
 		}
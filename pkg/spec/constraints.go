@@ -0,0 +1,13 @@
+package spec
+
+// ValueConstraints describes limits of a property or an argument value. A nil field means there is no limit.
+type ValueConstraints struct {
+	MinLength *int
+	MaxLength *int
+	Minimum   *float64
+	Maximum   *float64
+}
+
+func (vc ValueConstraints) IsEmpty() bool {
+	return vc.MinLength == nil && vc.MaxLength == nil && vc.Minimum == nil && vc.Maximum == nil
+}
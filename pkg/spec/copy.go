@@ -0,0 +1,112 @@
+package spec
+
+// CopyType returns the copy of the type from another spec with data types declared in this one.
+// Relations aren't copied, because parents and children of the copy must be types of this spec.
+func (as *ApiSpec) CopyType(t *TgTypeSpec) *TgTypeSpec {
+	c, _ := NewTgTypeSpec(t.category, t.name, t.link)
+	c.description = t.description
+	c.order = t.order
+	c.history = copyPointer(t.history)
+	c.provenance = copyProvenance(t.provenance)
+
+	for _, p := range t.properties {
+		cp, _ := NewTgTypeSpecProperty(p.name)
+		cp.description = p.description
+		cp.optional = p.optional
+		cp.predefinedValue = copyPointer(p.predefinedValue)
+		cp.enum = copyStrings(p.enum)
+		cp.constraints = copyConstraints(p.constraints)
+		cp.order = p.order
+		cp.history = copyPointer(p.history)
+		cp.provenance = copyProvenance(p.provenance)
+		for _, dt := range p.dataTypes {
+			cp.AddDataType(as.DeclareDataType(dt.GetDefinition()))
+		}
+		c.AddProperty(cp)
+	}
+
+	return c
+}
+
+// CopyMethod returns the copy of the method from another spec with data types declared in this one.
+func (as *ApiSpec) CopyMethod(m *TgMethodSpec) (*TgMethodSpec, error) {
+	c, _ := NewTgMethodSpec(m.category, m.name, m.link)
+	c.description = m.description
+	c.order = m.order
+	c.history = copyPointer(m.history)
+	c.provenance = copyProvenance(m.provenance)
+	for _, g := range m.argumentGroups {
+		c.argumentGroups = append(c.argumentGroups, &TgMethodSpecArgumentGroup{
+			kind:      g.kind,
+			arguments: copyStrings(g.arguments),
+			dependsOn: copyStrings(g.dependsOn),
+		})
+	}
+
+	for _, a := range m.arguments {
+		ca, _ := NewTgMethodSpecArgument(a.name)
+		ca.description = a.description
+		ca.required = a.required
+		ca.enum = copyStrings(a.enum)
+		ca.constraints = copyConstraints(a.constraints)
+		ca.defaultVal = a.defaultVal
+		ca.order = a.order
+		ca.history = copyPointer(a.history)
+		ca.provenance = copyProvenance(a.provenance)
+		for _, dt := range a.dataTypes {
+			ca.AddDataType(as.DeclareDataType(dt.GetDefinition()))
+		}
+		c.AddArgument(ca)
+	}
+
+	for _, dt := range m.returns {
+		c.AddReturnType(as.DeclareDataType(dt.GetDefinition()))
+	}
+
+	for _, cr := range m.conditionalReturns {
+		ccr, err := NewTgMethodSpecConditionalReturn(as.DeclareDataType(cr.dataType.GetDefinition()), cr.condition)
+		if err != nil {
+			return nil, err
+		}
+		ccr.SetArguments(copyStrings(cr.arguments))
+		c.AddConditionalReturn(ccr)
+	}
+
+	return c, nil
+}
+
+func copyStrings(values []string) []string {
+	return append([]string(nil), values...)
+}
+
+func copyPointer[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+
+	c := *p
+
+	return &c
+}
+
+func copyConstraints(c *ValueConstraints) *ValueConstraints {
+	if c == nil {
+		return nil
+	}
+
+	return &ValueConstraints{
+		MinLength: copyPointer(c.MinLength),
+		MaxLength: copyPointer(c.MaxLength),
+		Minimum:   copyPointer(c.Minimum),
+		Maximum:   copyPointer(c.Maximum),
+	}
+}
+
+func copyProvenance(p *Provenance) *Provenance {
+	c := copyPointer(p)
+	if c != nil {
+		c.Cells = copyStrings(p.Cells)
+	}
+
+	return c
+}
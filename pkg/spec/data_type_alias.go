@@ -0,0 +1,85 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var aliasNameRegexp = regexp.MustCompile(`^[A-Z][A-Za-z0-9_]+$`)
+
+// DataTypeAlias gives a name to the union data type which recurs across the spec, e.g. `FileInput` for `InputFile|string`.
+type DataTypeAlias struct {
+	name     string
+	dataType *UnionDataType
+}
+
+func (dta DataTypeAlias) GetName() string {
+	return dta.name
+}
+
+func (dta DataTypeAlias) GetDataType() *UnionDataType {
+	return dta.dataType
+}
+
+// DeclareAlias declares the union of the provided definitions and names it.
+// Declaring the same alias twice is allowed only for the same union.
+func (as *ApiSpec) DeclareAlias(name string, definitions ...string) (*DataTypeAlias, error) {
+	if !aliasNameRegexp.MatchString(name) {
+		return nil, errors.New(fmt.Sprintf("invalid alias name '%s'", name))
+	}
+
+	union, ok := as.DeclareUnionDataType(definitions...).(*UnionDataType)
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("alias %s must name a union of several data types", name))
+	}
+
+	as.al_mu.Lock()
+	defer as.al_mu.Unlock()
+
+	if alias, exists := as.aliases[name]; exists {
+		if alias.dataType != union {
+			return nil, errors.New(fmt.Sprintf("alias %s is already declared for %s", name, alias.dataType.GetDefinition()))
+		}
+
+		return alias, nil
+	}
+
+	if union.alias != nil {
+		return nil, errors.New(fmt.Sprintf("data type %s already has the alias %s", union.GetDefinition(), union.alias.name))
+	}
+
+	alias := &DataTypeAlias{name: name, dataType: union}
+	union.setAlias(alias)
+	as.aliases[name] = alias
+
+	return alias, nil
+}
+
+// RemoveAlias removes only the name, the union data type stays declared. It returns false if the alias doesn't exist.
+func (as *ApiSpec) RemoveAlias(name string) bool {
+	as.al_mu.Lock()
+	defer as.al_mu.Unlock()
+
+	alias, exists := as.aliases[name]
+	if !exists {
+		return false
+	}
+
+	alias.dataType.setAlias(nil)
+	delete(as.aliases, name)
+
+	return true
+}
+
+func (as ApiSpec) GetAlias(name string) (*DataTypeAlias, bool) {
+	as.al_mu.RLock()
+	alias, exists := as.aliases[name]
+	as.al_mu.RUnlock()
+
+	return alias, exists
+}
+
+func (as ApiSpec) GetAliases() map[string]*DataTypeAlias {
+	return as.aliases
+}
@@ -1,6 +1,10 @@
 package spec
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
 	"strings"
 )
 
@@ -20,6 +24,75 @@ type ScalarDataType struct {
 	abstractDataType
 }
 
+// IsLiteral reports whether the scalar type allows only one value (e.g. `true`).
+func (s ScalarDataType) IsLiteral() bool {
+	return s.definition == "true"
+}
+
+// ParseValue converts the raw text (as written in the official doc) to the Go value of the scalar type:
+// int64 for integers, float64 for floats, bool for booleans and string for strings.
+func (s ScalarDataType) ParseValue(raw string) (interface{}, error) {
+	switch s.definition {
+	case "int32", "int64":
+		return strconv.ParseInt(raw, 10, 64)
+	case "float":
+		return strconv.ParseFloat(raw, 64)
+	case "boolean":
+		switch strings.ToLower(raw) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	case "true":
+		if strings.ToLower(raw) == "true" {
+			return true, nil
+		}
+	case "string":
+		return raw, nil
+	}
+
+	return nil, errors.New(fmt.Sprintf("can't parse '%s' as %s", raw, s.definition))
+}
+
+// coerceValue converts the value to the Go type of the scalar type if it's possible without losing data.
+// It's useful for values decoded from JSON, where all numbers are float64.
+func (s ScalarDataType) coerceValue(value interface{}) (interface{}, bool) {
+	switch s.definition {
+	case "int32", "int64":
+		switch v := value.(type) {
+		case int:
+			return int64(v), true
+		case int64:
+			return v, true
+		case float64:
+			if v == math.Trunc(v) {
+				return int64(v), true
+			}
+		}
+	case "float":
+		switch v := value.(type) {
+		case int:
+			return float64(v), true
+		case int64:
+			return float64(v), true
+		case float64:
+			return v, true
+		}
+	case "boolean":
+		v, ok := value.(bool)
+		return v, ok
+	case "true":
+		v, ok := value.(bool)
+		return v, ok && v
+	case "string":
+		v, ok := value.(string)
+		return v, ok
+	}
+
+	return nil, false
+}
+
 type ObjectDataType struct {
 	abstractDataType
 	ref *TgTypeSpec
@@ -35,44 +108,128 @@ func (o ObjectDataType) GetRef() *TgTypeSpec {
 
 type ArrayDataType struct {
 	abstractDataType
-	elementAnyOf []DataTypeDefinition
+	element DataTypeDefinition
+}
+
+// GetElementDataType returns the data type of array elements (a union if elements can be of several data types)
+// or nil if it's unknown.
+func (a ArrayDataType) GetElementDataType() DataTypeDefinition {
+	return a.element
 }
 
+// GetElementDataTypes returns the list of data types which array elements can be.
 func (a ArrayDataType) GetElementDataTypes() []DataTypeDefinition {
-	return a.elementAnyOf
+	if a.element == nil {
+		return []DataTypeDefinition{}
+	}
+
+	return FlattenDataTypes([]DataTypeDefinition{a.element})
+}
+
+// UnionDataType describes a value which can be one of several data types, e.g. `int64|string`.
+type UnionDataType struct {
+	abstractDataType
+	members []DataTypeDefinition
+	alias   *DataTypeAlias
+}
+
+func (u UnionDataType) GetMemberDataTypes() []DataTypeDefinition {
+	return u.members
+}
+
+func (u *UnionDataType) setAlias(alias *DataTypeAlias) {
+	u.alias = alias
 }
 
+// GetAlias returns the alias of the union or nil if the union isn't named.
+func (u UnionDataType) GetAlias() *DataTypeAlias {
+	return u.alias
+}
+
+// FlattenDataTypes replaces unions in the list by their members.
+func FlattenDataTypes(dataTypes []DataTypeDefinition) []DataTypeDefinition {
+	var flattened []DataTypeDefinition
+	for _, dt := range dataTypes {
+		if union, ok := dt.(*UnionDataType); ok {
+			flattened = append(flattened, union.GetMemberDataTypes()...)
+		} else {
+			flattened = append(flattened, dt)
+		}
+	}
+
+	return flattened
+}
+
+// newDataTypeDefinition creates the data type from the definition. A definition which can't be parsed
+// is treated as an object with an unresolvable reference, so it will be reported by the spec self check.
 func newDataTypeDefinition(definition string, as *ApiSpec) DataTypeDefinition {
-	switch {
-	case strings.HasPrefix(definition, "array"):
-		elementAnyOf := make([]DataTypeDefinition, 0)
-		if strings.HasPrefix(definition, "array<") && strings.HasSuffix(definition, ">") {
-			elementDefinitions := strings.Split(
-				strings.TrimPrefix(
-					strings.TrimSuffix(definition, ">"),
-					"array<",
-				),
-				"|",
-			)
-
-			for _, elementDefinition := range elementDefinitions {
-				elementAnyOf = append(elementAnyOf, as.DeclareDataType(elementDefinition))
-			}
+	expr, err := ParseTypeExpression(definition)
+	if err != nil {
+		return &ObjectDataType{abstractDataType: abstractDataType{definition}}
+	}
+
+	definition = expr.String()
+	switch e := expr.(type) {
+	case *ArrayTypeExpression:
+		var element DataTypeDefinition
+		if e.Element != nil {
+			element = as.DeclareDataType(e.Element.String())
+		}
+
+		return &ArrayDataType{abstractDataType: abstractDataType{definition}, element: element}
+	case *UnionTypeExpression:
+		members := make([]DataTypeDefinition, len(e.Members))
+		for i, member := range e.Members {
+			members[i] = as.DeclareDataType(member.String())
 		}
 
-		return &ArrayDataType{abstractDataType: abstractDataType{definition}, elementAnyOf: elementAnyOf}
-	case isScalar(definition):
+		return &UnionDataType{abstractDataType: abstractDataType{definition}, members: members}
+	case *ScalarTypeExpression:
 		return &ScalarDataType{abstractDataType: abstractDataType{definition}}
 	default:
 		return &ObjectDataType{abstractDataType: abstractDataType{definition}}
 	}
 }
 
+// canonicalDefinition returns the canonical form of the definition or the definition itself if it can't be parsed.
+func canonicalDefinition(definition string) string {
+	expr, err := ParseTypeExpression(definition)
+	if err != nil {
+		return definition
+	}
+
+	return expr.String()
+}
+
 func isScalar(definition string) bool {
 	switch definition {
-	case "string", "int32", "int64", "float", "boolean":
+	case "string", "int32", "int64", "float", "boolean", "true":
 		return true
 	}
 
 	return false
 }
+
+// ReferencedTypes returns the Telegram types which the data type refers to, including elements of arrays and members of unions.
+// Object data types without a reference are skipped.
+func ReferencedTypes(dataType DataTypeDefinition) []*TgTypeSpec {
+	switch dt := dataType.(type) {
+	case *ObjectDataType:
+		if dt.GetRef() != nil {
+			return []*TgTypeSpec{dt.GetRef()}
+		}
+	case *ArrayDataType:
+		if dt.GetElementDataType() != nil {
+			return ReferencedTypes(dt.GetElementDataType())
+		}
+	case *UnionDataType:
+		var types []*TgTypeSpec
+		for _, member := range dt.GetMemberDataTypes() {
+			types = append(types, ReferencedTypes(member)...)
+		}
+
+		return types
+	}
+
+	return nil
+}
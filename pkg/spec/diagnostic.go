@@ -0,0 +1,127 @@
+package spec
+
+import (
+	"fmt"
+	"sort"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// DiagnosticCode identifies the kind of the finding. Codes are stable, so they can be used to filter or suppress findings.
+type DiagnosticCode string
+
+const (
+	CodeMissingVersion              DiagnosticCode = "missing-version"
+	CodeMissingReleaseDate          DiagnosticCode = "missing-release-date"
+	CodeMissingLink                 DiagnosticCode = "missing-link"
+	CodeUnresolvedReference         DiagnosticCode = "unresolved-reference"
+	CodeMissingDataType             DiagnosticCode = "missing-data-type"
+	CodeMissingReturnType           DiagnosticCode = "missing-return-type"
+	CodeMissingParent               DiagnosticCode = "missing-parent"
+	CodeMissingChild                DiagnosticCode = "missing-child"
+	CodeAsymmetricRelation          DiagnosticCode = "asymmetric-relation"
+	CodeInconsistentDiscriminator   DiagnosticCode = "inconsistent-discriminator"
+	CodeUndeterminedDiscriminator   DiagnosticCode = "undetermined-discriminator"
+	CodeDuplicateDiscriminatorValue DiagnosticCode = "duplicate-discriminator-value"
+	CodeUnknownConditionalReturn    DiagnosticCode = "unknown-conditional-return"
+	CodeUnknownArgument             DiagnosticCode = "unknown-argument"
+	CodeAliasConflict               DiagnosticCode = "alias-conflict"
+	CodeMissingDescription          DiagnosticCode = "missing-description"
+)
+
+// Diagnostic is a finding of the spec check. Path points to the element, e.g. `types.Message.properties.chat`.
+type Diagnostic struct {
+	Code     DiagnosticCode `json:"code"`
+	Severity Severity       `json:"severity"`
+	Path     string         `json:"path"`
+	Message  string         `json:"message"`
+}
+
+func (d Diagnostic) Error() string {
+	return fmt.Sprintf("[%s] %s: %s", d.Code, d.Path, d.Message)
+}
+
+type Diagnostics []Diagnostic
+
+// Filter returns diagnostics of the provided severity.
+func (ds Diagnostics) Filter(severity Severity) Diagnostics {
+	var filtered Diagnostics
+	for _, d := range ds {
+		if d.Severity == severity {
+			filtered = append(filtered, d)
+		}
+	}
+
+	return filtered
+}
+
+func (ds Diagnostics) Errors() Diagnostics {
+	return ds.Filter(SeverityError)
+}
+
+func (ds Diagnostics) Warnings() Diagnostics {
+	return ds.Filter(SeverityWarning)
+}
+
+func (ds Diagnostics) HasErrors() bool {
+	return len(ds.Errors()) > 0
+}
+
+// Err returns the CompositeError of error diagnostics or nil if there are no errors.
+func (ds Diagnostics) Err() error {
+	errs := ds.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	problems := make([]error, len(errs))
+	for i, d := range errs {
+		problems[i] = d
+	}
+
+	return NewCompositeError(problems)
+}
+
+func (ds Diagnostics) sort() {
+	sort.SliceStable(ds, func(i, j int) bool {
+		if ds[i].Path != ds[j].Path {
+			return ds[i].Path < ds[j].Path
+		}
+
+		return ds[i].Code < ds[j].Code
+	})
+}
+
+func newError(code DiagnosticCode, path, format string, args ...interface{}) Diagnostic {
+	return Diagnostic{Code: code, Severity: SeverityError, Path: path, Message: fmt.Sprintf(format, args...)}
+}
+
+func newWarning(code DiagnosticCode, path, format string, args ...interface{}) Diagnostic {
+	return Diagnostic{Code: code, Severity: SeverityWarning, Path: path, Message: fmt.Sprintf(format, args...)}
+}
+
+func newInfo(code DiagnosticCode, path, format string, args ...interface{}) Diagnostic {
+	return Diagnostic{Code: code, Severity: SeverityInfo, Path: path, Message: fmt.Sprintf(format, args...)}
+}
+
+func typePath(t *TgTypeSpec) string {
+	return "types." + t.GetName()
+}
+
+func propertyPath(t *TgTypeSpec, p *TgTypeSpecProperty) string {
+	return typePath(t) + ".properties." + p.GetName()
+}
+
+func methodPath(m *TgMethodSpec) string {
+	return "methods." + m.GetName()
+}
+
+func argumentPath(m *TgMethodSpec, a *TgMethodSpecArgument) string {
+	return methodPath(m) + ".arguments." + a.GetName()
+}
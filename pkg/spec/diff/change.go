@@ -0,0 +1,129 @@
+package spec_diff
+
+import (
+	"sort"
+)
+
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"
+	Removed ChangeKind = "removed"
+	Changed ChangeKind = "changed"
+)
+
+// Impact describes how a change affects the code generated from the spec.
+type Impact string
+
+const (
+	// Breaking changes can break the existing generated clients (removed fields, narrowed types, etc.).
+	Breaking Impact = "breaking"
+	// Additive changes extend the spec without breaking the existing generated clients.
+	Additive Impact = "additive"
+	// Patch changes affect only the documentation (descriptions, links, categories).
+	Patch Impact = "patch"
+)
+
+type ElementKind string
+
+const (
+	ElementType     ElementKind = "type"
+	ElementMethod   ElementKind = "method"
+	ElementProperty ElementKind = "property"
+	ElementArgument ElementKind = "argument"
+)
+
+// Bump is a semver part that should be increased according to the detected changes.
+type Bump string
+
+const (
+	BumpNone  Bump = "none"
+	BumpPatch Bump = "patch"
+	BumpMinor Bump = "minor"
+	BumpMajor Bump = "major"
+)
+
+// Change describes a single difference between two specs.
+// Attribute is empty when the element itself was added or removed.
+type Change struct {
+	Kind      ChangeKind  `json:"kind"`
+	Impact    Impact      `json:"impact"`
+	Element   ElementKind `json:"element"`
+	Path      string      `json:"path"`
+	Attribute string      `json:"attribute,omitempty"`
+	Old       string      `json:"old,omitempty"`
+	New       string      `json:"new,omitempty"`
+}
+
+type ChangeSet struct {
+	OldVersion string   `json:"oldVersion"`
+	NewVersion string   `json:"newVersion"`
+	Changes    []Change `json:"changes"`
+}
+
+func (cs ChangeSet) IsEmpty() bool {
+	return len(cs.Changes) == 0
+}
+
+func (cs ChangeSet) HasBreaking() bool {
+	for _, c := range cs.Changes {
+		if c.Impact == Breaking {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (cs ChangeSet) Filter(impact Impact) []Change {
+	var changes []Change
+	for _, c := range cs.Changes {
+		if c.Impact == impact {
+			changes = append(changes, c)
+		}
+	}
+
+	return changes
+}
+
+func (cs ChangeSet) Bump() Bump {
+	bump := BumpNone
+	for _, c := range cs.Changes {
+		switch c.Impact {
+		case Breaking:
+			return BumpMajor
+		case Additive:
+			bump = BumpMinor
+		case Patch:
+			if bump == BumpNone {
+				bump = BumpPatch
+			}
+		}
+	}
+
+	return bump
+}
+
+func (cs *ChangeSet) add(change Change) {
+	cs.Changes = append(cs.Changes, change)
+}
+
+func (cs *ChangeSet) sort() {
+	sort.SliceStable(cs.Changes, func(i, j int) bool {
+		a, b := cs.Changes[i], cs.Changes[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Attribute != b.Attribute {
+			return a.Attribute < b.Attribute
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Old != b.Old {
+			return a.Old < b.Old
+		}
+
+		return a.New < b.New
+	})
+}
@@ -0,0 +1,381 @@
+package spec_diff
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+// Compare returns the set of changes required to turn the `from` spec into the `to` spec.
+func Compare(from, to *spec.ApiSpec) *ChangeSet {
+	cs := &ChangeSet{
+		OldVersion: from.GetVersion(),
+		NewVersion: to.GetVersion(),
+		Changes:    []Change{},
+	}
+
+	compareTypes(cs, from.GetTypes(), to.GetTypes())
+	compareMethods(cs, from.GetMethods(), to.GetMethods())
+
+	cs.sort()
+
+	return cs
+}
+
+func compareTypes(cs *ChangeSet, from, to map[string]*spec.TgTypeSpec) {
+	for _, name := range unionKeys(from, to) {
+		path := "types." + name
+		old, oldExists := from[name]
+		cur, curExists := to[name]
+
+		switch {
+		case !oldExists:
+			cs.add(Change{Kind: Added, Impact: Additive, Element: ElementType, Path: path})
+		case !curExists:
+			cs.add(Change{Kind: Removed, Impact: Breaking, Element: ElementType, Path: path})
+		default:
+			compareCommon(cs, ElementType, path, old.GetCategory(), cur.GetCategory(), old.GetLink(), cur.GetLink(), old.GetDescription(), cur.GetDescription())
+			compareSet(cs, ElementType, path, "parents", typeNames(old.GetParents()...), typeNames(cur.GetParents()...), Breaking, Additive)
+			compareSet(cs, ElementType, path, "children", typeNames(old.GetChildren()...), typeNames(cur.GetChildren()...), Breaking, Additive)
+			compareProperties(cs, path, old.GetProperties(), cur.GetProperties())
+		}
+	}
+}
+
+func compareProperties(cs *ChangeSet, typePath string, from, to []*spec.TgTypeSpecProperty) {
+	fromByName := make(map[string]*spec.TgTypeSpecProperty)
+	for _, p := range from {
+		fromByName[p.GetName()] = p
+	}
+	toByName := make(map[string]*spec.TgTypeSpecProperty)
+	for _, p := range to {
+		toByName[p.GetName()] = p
+	}
+
+	for _, name := range unionKeys(fromByName, toByName) {
+		path := typePath + ".properties." + name
+		old, oldExists := fromByName[name]
+		cur, curExists := toByName[name]
+
+		switch {
+		case !oldExists:
+			cs.add(Change{Kind: Added, Impact: Additive, Element: ElementProperty, Path: path})
+		case !curExists:
+			cs.add(Change{Kind: Removed, Impact: Breaking, Element: ElementProperty, Path: path})
+		default:
+			compareDescription(cs, ElementProperty, path, old.GetDescription(), cur.GetDescription())
+			removedImpact, addedImpact := memberImpacts(ElementProperty)
+			compareSet(cs, ElementProperty, path, "types", definitions(old.GetDataTypes()), definitions(cur.GetDataTypes()), removedImpact, addedImpact)
+
+			if old.IsOptional() != cur.IsOptional() {
+				impact := Additive
+				if cur.IsOptional() {
+					impact = Breaking
+				}
+				cs.add(Change{
+					Kind:      Changed,
+					Impact:    impact,
+					Element:   ElementProperty,
+					Path:      path,
+					Attribute: "optional",
+					Old:       strconv.FormatBool(old.IsOptional()),
+					New:       strconv.FormatBool(cur.IsOptional()),
+				})
+			}
+
+			compareValue(cs, ElementProperty, path, "default", predefinedValue(old), predefinedValue(cur))
+			compareEnum(cs, ElementProperty, path, old.GetEnum(), cur.GetEnum())
+			compareConstraints(cs, ElementProperty, path, old.GetConstraints(), cur.GetConstraints())
+		}
+	}
+}
+
+func compareMethods(cs *ChangeSet, from, to map[string]*spec.TgMethodSpec) {
+	for _, name := range unionKeys(from, to) {
+		path := "methods." + name
+		old, oldExists := from[name]
+		cur, curExists := to[name]
+
+		switch {
+		case !oldExists:
+			cs.add(Change{Kind: Added, Impact: Additive, Element: ElementMethod, Path: path})
+		case !curExists:
+			cs.add(Change{Kind: Removed, Impact: Breaking, Element: ElementMethod, Path: path})
+		default:
+			compareCommon(cs, ElementMethod, path, old.GetCategory(), cur.GetCategory(), old.GetLink(), cur.GetLink(), old.GetDescription(), cur.GetDescription())
+			removedImpact, addedImpact := memberImpacts(ElementMethod)
+			compareSet(cs, ElementMethod, path, "returns", definitions(old.GetReturnTypes()), definitions(cur.GetReturnTypes()), removedImpact, addedImpact)
+			compareArguments(cs, path, old.GetArguments(), cur.GetArguments())
+			compareSet(cs, ElementMethod, path, "conditionalReturns", conditionalReturns(old), conditionalReturns(cur), Breaking, Additive)
+			compareSet(cs, ElementMethod, path, "argumentGroups", argumentGroups(old), argumentGroups(cur), Additive, Breaking)
+		}
+	}
+}
+
+func compareArguments(cs *ChangeSet, methodPath string, from, to []*spec.TgMethodSpecArgument) {
+	fromByName := make(map[string]*spec.TgMethodSpecArgument)
+	for _, a := range from {
+		fromByName[a.GetName()] = a
+	}
+	toByName := make(map[string]*spec.TgMethodSpecArgument)
+	for _, a := range to {
+		toByName[a.GetName()] = a
+	}
+
+	for _, name := range unionKeys(fromByName, toByName) {
+		path := methodPath + ".arguments." + name
+		old, oldExists := fromByName[name]
+		cur, curExists := toByName[name]
+
+		switch {
+		case !oldExists:
+			impact := Additive
+			if cur.IsRequired() {
+				impact = Breaking
+			}
+			cs.add(Change{Kind: Added, Impact: impact, Element: ElementArgument, Path: path})
+		case !curExists:
+			cs.add(Change{Kind: Removed, Impact: Breaking, Element: ElementArgument, Path: path})
+		default:
+			compareDescription(cs, ElementArgument, path, old.GetDescription(), cur.GetDescription())
+			removedImpact, addedImpact := memberImpacts(ElementArgument)
+			compareSet(cs, ElementArgument, path, "types", definitions(old.GetDataTypes()), definitions(cur.GetDataTypes()), removedImpact, addedImpact)
+			compareEnum(cs, ElementArgument, path, old.GetEnum(), cur.GetEnum())
+			compareConstraints(cs, ElementArgument, path, old.GetConstraints(), cur.GetConstraints())
+			compareValue(cs, ElementArgument, path, "default", formatValue(old.GetDefault()), formatValue(cur.GetDefault()))
+
+			if old.IsRequired() != cur.IsRequired() {
+				impact := Additive
+				if cur.IsRequired() {
+					impact = Breaking
+				}
+				cs.add(Change{
+					Kind:      Changed,
+					Impact:    impact,
+					Element:   ElementArgument,
+					Path:      path,
+					Attribute: "required",
+					Old:       strconv.FormatBool(old.IsRequired()),
+					New:       strconv.FormatBool(cur.IsRequired()),
+				})
+			}
+		}
+	}
+}
+
+func compareCommon(cs *ChangeSet, element ElementKind, path, oldCategory, newCategory, oldLink, newLink, oldDescription, newDescription string) {
+	if oldCategory != newCategory {
+		cs.add(Change{Kind: Changed, Impact: Patch, Element: element, Path: path, Attribute: "category", Old: oldCategory, New: newCategory})
+	}
+
+	if oldLink != newLink {
+		cs.add(Change{Kind: Changed, Impact: Patch, Element: element, Path: path, Attribute: "link", Old: oldLink, New: newLink})
+	}
+
+	compareDescription(cs, element, path, oldDescription, newDescription)
+}
+
+func compareDescription(cs *ChangeSet, element ElementKind, path, old, new string) {
+	if old != new {
+		cs.add(Change{Kind: Changed, Impact: Patch, Element: element, Path: path, Attribute: "description", Old: old, New: new})
+	}
+}
+
+// compareValue compares optional values, where an empty string means that the value is not set.
+// Setting a value is additive, while changing or removing it is breaking.
+func compareValue(cs *ChangeSet, element ElementKind, path, attribute, old, new string) {
+	switch {
+	case old == new:
+		return
+	case old == "":
+		cs.add(Change{Kind: Added, Impact: Additive, Element: element, Path: path, Attribute: attribute, New: new})
+	case new == "":
+		cs.add(Change{Kind: Removed, Impact: Breaking, Element: element, Path: path, Attribute: attribute, Old: old})
+	default:
+		cs.add(Change{Kind: Changed, Impact: Breaking, Element: element, Path: path, Attribute: attribute, Old: old, New: new})
+	}
+}
+
+// compareConstraints classifies changes of limits by direction. Narrowing the accepted input of arguments is breaking
+// and widening it is additive, while for properties of responses it's the other way around.
+func compareConstraints(cs *ChangeSet, element ElementKind, path string, old, new *spec.ValueConstraints) {
+	if old == nil {
+		old = &spec.ValueConstraints{}
+	}
+	if new == nil {
+		new = &spec.ValueConstraints{}
+	}
+
+	compareLimit(cs, element, path, "minLength", intToFloat(old.MinLength), intToFloat(new.MinLength), false)
+	compareLimit(cs, element, path, "maxLength", intToFloat(old.MaxLength), intToFloat(new.MaxLength), true)
+	compareLimit(cs, element, path, "minimum", old.Minimum, new.Minimum, false)
+	compareLimit(cs, element, path, "maximum", old.Maximum, new.Maximum, true)
+}
+
+// compareLimit compares the lower or the upper limit, where nil means that there is no limit.
+func compareLimit(cs *ChangeSet, element ElementKind, path, attribute string, old, new *float64, upper bool) {
+	change := Change{Kind: Changed, Element: element, Path: path, Attribute: attribute, Old: formatFloat(old), New: formatFloat(new)}
+
+	var narrowed bool
+	switch {
+	case old == nil && new == nil, old != nil && new != nil && *old == *new:
+		return
+	case old == nil:
+		change.Kind, narrowed = Added, true
+	case new == nil:
+		change.Kind, narrowed = Removed, false
+	case upper:
+		narrowed = *new < *old
+	default:
+		narrowed = *new > *old
+	}
+
+	// Arguments are sent by clients, so narrowing breaks them. Properties are received, so widening breaks them.
+	if narrowed == (element == ElementArgument) {
+		change.Impact = Breaking
+	} else {
+		change.Impact = Additive
+	}
+
+	cs.add(change)
+}
+
+// memberImpacts returns impacts of removed and added members of unions and enums of the element.
+// Clients send arguments, so removed members break them. Properties and returned types are received,
+// so new members which clients can't decode break them.
+func memberImpacts(element ElementKind) (removed, added Impact) {
+	if element == ElementArgument {
+		return Breaking, Additive
+	}
+
+	return Additive, Breaking
+}
+
+// compareEnum reports introducing or dropping the enum as one change, as the element without the enum takes any value.
+// The new enum narrows sent arguments, but only documents values of received properties.
+func compareEnum(cs *ChangeSet, element ElementKind, path string, old, new []string) {
+	introducedImpact, droppedImpact := Additive, Breaking
+	if element == ElementArgument {
+		introducedImpact, droppedImpact = Breaking, Additive
+	}
+
+	switch {
+	case len(old) == 0 && len(new) > 0:
+		cs.add(Change{Kind: Added, Impact: introducedImpact, Element: element, Path: path, Attribute: "enum", New: strings.Join(new, ", ")})
+	case len(old) > 0 && len(new) == 0:
+		cs.add(Change{Kind: Removed, Impact: droppedImpact, Element: element, Path: path, Attribute: "enum", Old: strings.Join(old, ", ")})
+	default:
+		removedImpact, addedImpact := memberImpacts(element)
+		compareSet(cs, element, path, "enum", old, new, removedImpact, addedImpact)
+	}
+}
+
+func compareSet(cs *ChangeSet, element ElementKind, path, attribute string, old, new []string, removedImpact, addedImpact Impact) {
+	oldSet := make(map[string]bool)
+	for _, v := range old {
+		oldSet[v] = true
+	}
+	newSet := make(map[string]bool)
+	for _, v := range new {
+		newSet[v] = true
+	}
+
+	for _, v := range unionKeys(oldSet, newSet) {
+		switch {
+		case !oldSet[v]:
+			cs.add(Change{Kind: Added, Impact: addedImpact, Element: element, Path: path, Attribute: attribute, New: v})
+		case !newSet[v]:
+			cs.add(Change{Kind: Removed, Impact: removedImpact, Element: element, Path: path, Attribute: attribute, Old: v})
+		}
+	}
+}
+
+func definitions(dataTypes []spec.DataTypeDefinition) []string {
+	var defs []string
+	for _, dt := range spec.FlattenDataTypes(dataTypes) {
+		defs = append(defs, dt.GetDefinition())
+	}
+
+	return defs
+}
+
+func conditionalReturns(m *spec.TgMethodSpec) []string {
+	var returns []string
+	for _, cr := range m.GetConditionalReturns() {
+		returns = append(returns, cr.String())
+	}
+
+	return returns
+}
+
+func argumentGroups(m *spec.TgMethodSpec) []string {
+	var groups []string
+	for _, g := range m.GetArgumentGroups() {
+		groups = append(groups, g.String())
+	}
+
+	return groups
+}
+
+func typeNames(types ...*spec.TgTypeSpec) []string {
+	var names []string
+	for _, t := range types {
+		if t != nil {
+			names = append(names, t.GetName())
+		}
+	}
+
+	return names
+}
+
+func predefinedValue(p *spec.TgTypeSpecProperty) string {
+	if p.GetPredefinedValue() == nil {
+		return ""
+	}
+
+	return string(*p.GetPredefinedValue())
+}
+
+func intToFloat(v *int) *float64 {
+	if v == nil {
+		return nil
+	}
+
+	f := float64(*v)
+
+	return &f
+}
+
+func formatFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+func formatValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	var keys []string
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, exists := a[k]; !exists {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
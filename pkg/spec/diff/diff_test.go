@@ -0,0 +1,318 @@
+package spec_diff
+
+import (
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+// element describes the `Message.text` property and the `sendMessage.text` argument of the hand-built spec.
+type element struct {
+	types       []string
+	enum        []string
+	constraints *spec.ValueConstraints
+}
+
+func newSpec(t *testing.T, property, argument element) *spec.ApiSpec {
+	t.Helper()
+
+	return spectest.New(t, func(as *spec.ApiSpec) error {
+		as.SetVersion("1.0")
+
+		message := spectest.AddType(as, "Message")
+		p, _ := spec.NewTgTypeSpecProperty("text")
+		for _, def := range property.types {
+			p.AddDataType(as.DeclareDataType(def))
+		}
+		p.SetEnum(property.enum)
+		p.SetConstraints(property.constraints)
+		message.AddProperty(p)
+
+		method := spectest.AddMethod(as, "sendMessage", "Message")
+		a, _ := spec.NewTgMethodSpecArgument("text")
+		for _, def := range argument.types {
+			a.AddDataType(as.DeclareDataType(def))
+		}
+		a.SetEnum(argument.enum)
+		a.SetConstraints(argument.constraints)
+		method.AddArgument(a)
+
+		return nil
+	})
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func findChange(cs *ChangeSet, path, attribute string) (Change, bool) {
+	for _, c := range cs.Changes {
+		if c.Path == path && c.Attribute == attribute {
+			return c, true
+		}
+	}
+
+	return Change{}, false
+}
+
+func TestCompareConstraints(t *testing.T) {
+	limit := func(min, max *int) *spec.ValueConstraints {
+		return &spec.ValueConstraints{MinLength: min, MaxLength: max}
+	}
+
+	tests := []struct {
+		name      string
+		element   ElementKind
+		attribute string
+		old, new  *spec.ValueConstraints
+		kind      ChangeKind
+		impact    Impact
+	}{
+		{"argument max widened", ElementArgument, "maxLength", limit(nil, intPtr(1024)), limit(nil, intPtr(4096)), Changed, Additive},
+		{"argument max narrowed", ElementArgument, "maxLength", limit(nil, intPtr(4096)), limit(nil, intPtr(1024)), Changed, Breaking},
+		{"argument min raised", ElementArgument, "minLength", limit(intPtr(0), nil), limit(intPtr(1), nil), Changed, Breaking},
+		{"argument min lowered", ElementArgument, "minLength", limit(intPtr(1), nil), limit(intPtr(0), nil), Changed, Additive},
+		{"argument limit added", ElementArgument, "maxLength", nil, limit(nil, intPtr(4096)), Added, Breaking},
+		{"argument limit removed", ElementArgument, "maxLength", limit(nil, intPtr(4096)), nil, Removed, Additive},
+		{"property max widened", ElementProperty, "maxLength", limit(nil, intPtr(1024)), limit(nil, intPtr(4096)), Changed, Breaking},
+		{"property max narrowed", ElementProperty, "maxLength", limit(nil, intPtr(4096)), limit(nil, intPtr(1024)), Changed, Additive},
+		{"property limit added", ElementProperty, "maxLength", nil, limit(nil, intPtr(4096)), Added, Additive},
+		{"property limit removed", ElementProperty, "maxLength", limit(nil, intPtr(4096)), nil, Removed, Breaking},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plain := element{types: []string{"string"}}
+			limited := func(c *spec.ValueConstraints) element {
+				return element{types: []string{"string"}, constraints: c}
+			}
+
+			var cs *ChangeSet
+			path := "methods.sendMessage.arguments.text"
+			if tt.element == ElementProperty {
+				path = "types.Message.properties.text"
+				cs = Compare(newSpec(t, limited(tt.old), plain), newSpec(t, limited(tt.new), plain))
+			} else {
+				cs = Compare(newSpec(t, plain, limited(tt.old)), newSpec(t, plain, limited(tt.new)))
+			}
+
+			assertChange(t, cs, path, tt.attribute, tt.kind, tt.impact)
+		})
+	}
+}
+
+func assertChange(t *testing.T, cs *ChangeSet, path, attribute string, kind ChangeKind, impact Impact) {
+	t.Helper()
+
+	c, found := findChange(cs, path, attribute)
+	if !found {
+		t.Fatalf("no change of %s at %s in %+v", attribute, path, cs.Changes)
+	}
+	if c.Kind != kind || c.Impact != impact {
+		t.Errorf("got %s %s change of %s at %s, want %s %s", c.Impact, c.Kind, attribute, path, impact, kind)
+	}
+}
+
+func TestCompareUnions(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		old, new element
+		kind     ChangeKind
+		impact   Impact
+	}{
+		{"argument member added", "methods.sendMessage.arguments.text", element{types: []string{"string"}}, element{types: []string{"string", "int64"}}, Added, Additive},
+		{"argument member removed", "methods.sendMessage.arguments.text", element{types: []string{"string", "int64"}}, element{types: []string{"string"}}, Removed, Breaking},
+		{"property member added", "types.Message.properties.text", element{types: []string{"string"}}, element{types: []string{"string", "int64"}}, Added, Breaking},
+		{"property member removed", "types.Message.properties.text", element{types: []string{"string", "int64"}}, element{types: []string{"string"}}, Removed, Additive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plain := element{types: []string{"string"}}
+
+			var cs *ChangeSet
+			if tt.path == "types.Message.properties.text" {
+				cs = Compare(newSpec(t, tt.old, plain), newSpec(t, tt.new, plain))
+			} else {
+				cs = Compare(newSpec(t, plain, tt.old), newSpec(t, plain, tt.new))
+			}
+
+			assertChange(t, cs, tt.path, "types", tt.kind, tt.impact)
+		})
+	}
+}
+
+func TestCompareEnums(t *testing.T) {
+	plain := element{types: []string{"string"}}
+	enum := func(values ...string) element {
+		return element{types: []string{"string"}, enum: values}
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		old, new element
+		kind     ChangeKind
+		impact   Impact
+		changes  int
+	}{
+		{"argument enum introduced", "methods.sendMessage.arguments.text", plain, enum("a", "b", "c"), Added, Breaking, 1},
+		{"argument enum dropped", "methods.sendMessage.arguments.text", enum("a", "b", "c"), plain, Removed, Additive, 1},
+		{"argument value added", "methods.sendMessage.arguments.text", enum("a"), enum("a", "b"), Added, Additive, 1},
+		{"property enum introduced", "types.Message.properties.text", plain, enum("private", "group", "supergroup", "channel"), Added, Additive, 1},
+		{"property enum dropped", "types.Message.properties.text", enum("a", "b"), plain, Removed, Breaking, 1},
+		{"property value added", "types.Message.properties.text", enum("a"), enum("a", "b"), Added, Breaking, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cs *ChangeSet
+			if tt.path == "types.Message.properties.text" {
+				cs = Compare(newSpec(t, tt.old, plain), newSpec(t, tt.new, plain))
+			} else {
+				cs = Compare(newSpec(t, plain, tt.old), newSpec(t, plain, tt.new))
+			}
+
+			if len(cs.Changes) != tt.changes {
+				t.Errorf("got %d changes, want %d: %+v", len(cs.Changes), tt.changes, cs.Changes)
+			}
+			assertChange(t, cs, tt.path, "enum", tt.kind, tt.impact)
+		})
+	}
+}
+
+func TestCompareIdentical(t *testing.T) {
+	e := element{types: []string{"string", "int64"}, constraints: &spec.ValueConstraints{MaxLength: intPtr(4096)}}
+
+	if cs := Compare(newSpec(t, e, e), newSpec(t, e, e)); !cs.IsEmpty() {
+		t.Errorf("expected no changes, got %+v", cs.Changes)
+	}
+}
+
+// mutation changes the base spec, where `Message.caption` and `sendMessage.parse_mode` are optional.
+type mutation func(as *spec.ApiSpec)
+
+func newBaseSpec(t *testing.T, mutate mutation) *spec.ApiSpec {
+	t.Helper()
+
+	return spectest.New(t, func(as *spec.ApiSpec) error {
+		as.SetVersion("1.0")
+
+		message := spectest.AddType(as, "Message", "message_id int32", "caption string")
+		message.GetProperties()[1].SetOptional(true)
+		method := spectest.AddMethod(as, "sendMessage", "Message", "chat_id int64", "parse_mode string")
+		method.GetArguments()[1].SetRequired(false)
+
+		if mutate != nil {
+			mutate(as)
+		}
+
+		return nil
+	})
+}
+
+func addProperty(as *spec.ApiSpec) {
+	message, _ := as.GetType("Message")
+	p, _ := spec.NewTgTypeSpecProperty("date")
+	p.AddDataType(as.DeclareDataType("int32"))
+	message.AddProperty(p)
+}
+
+func addArgument(required bool) mutation {
+	return func(as *spec.ApiSpec) {
+		method, _ := as.GetMethod("sendMessage")
+		a, _ := spec.NewTgMethodSpecArgument("reply_to_message_id")
+		a.AddDataType(as.DeclareDataType("int32"))
+		a.SetRequired(required)
+		method.AddArgument(a)
+	}
+}
+
+func TestCompareImpacts(t *testing.T) {
+	addType := func(as *spec.ApiSpec) {
+		spectest.AddType(as, "Chat", "id int64")
+	}
+	addMethod := func(as *spec.ApiSpec) {
+		spectest.AddMethod(as, "getMe", "Message")
+	}
+	addReturnMember := func(as *spec.ApiSpec) {
+		method, _ := as.GetMethod("sendMessage")
+		method.AddReturnType(as.DeclareDataType("true"))
+	}
+	requireArgument := func(as *spec.ApiSpec) {
+		method, _ := as.GetMethod("sendMessage")
+		method.GetArguments()[1].SetRequired(true)
+	}
+	requireProperty := func(as *spec.ApiSpec) {
+		message, _ := as.GetType("Message")
+		message.GetProperties()[1].SetOptional(false)
+	}
+	describeMethod := func(as *spec.ApiSpec) {
+		method, _ := as.GetMethod("sendMessage")
+		method.SetDescription("Use this method to send text messages.")
+	}
+
+	tests := []struct {
+		name      string
+		from, to  mutation
+		path      string
+		attribute string
+		kind      ChangeKind
+		impact    Impact
+	}{
+		{"type added", nil, addType, "types.Chat", "", Added, Additive},
+		{"type removed", addType, nil, "types.Chat", "", Removed, Breaking},
+		{"method added", nil, addMethod, "methods.getMe", "", Added, Additive},
+		{"method removed", addMethod, nil, "methods.getMe", "", Removed, Breaking},
+		{"property added", nil, addProperty, "types.Message.properties.date", "", Added, Additive},
+		{"property removed", addProperty, nil, "types.Message.properties.date", "", Removed, Breaking},
+		{"optional argument added", nil, addArgument(false), "methods.sendMessage.arguments.reply_to_message_id", "", Added, Additive},
+		{"required argument added", nil, addArgument(true), "methods.sendMessage.arguments.reply_to_message_id", "", Added, Breaking},
+		{"argument removed", addArgument(false), nil, "methods.sendMessage.arguments.reply_to_message_id", "", Removed, Breaking},
+		{"argument made required", nil, requireArgument, "methods.sendMessage.arguments.parse_mode", "required", Changed, Breaking},
+		{"argument made optional", requireArgument, nil, "methods.sendMessage.arguments.parse_mode", "required", Changed, Additive},
+		{"property made required", nil, requireProperty, "types.Message.properties.caption", "optional", Changed, Additive},
+		{"property made optional", requireProperty, nil, "types.Message.properties.caption", "optional", Changed, Breaking},
+		{"return member added", nil, addReturnMember, "methods.sendMessage", "returns", Added, Breaking},
+		{"return member removed", addReturnMember, nil, "methods.sendMessage", "returns", Removed, Additive},
+		{"description changed", nil, describeMethod, "methods.sendMessage", "description", Changed, Patch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := Compare(newBaseSpec(t, tt.from), newBaseSpec(t, tt.to))
+
+			if len(cs.Changes) != 1 {
+				t.Errorf("got %d changes, want 1: %+v", len(cs.Changes), cs.Changes)
+			}
+			assertChange(t, cs, tt.path, tt.attribute, tt.kind, tt.impact)
+		})
+	}
+}
+
+func TestBump(t *testing.T) {
+	change := func(impact Impact) Change {
+		return Change{Kind: Changed, Impact: impact, Element: ElementMethod, Path: "methods.sendMessage"}
+	}
+
+	tests := []struct {
+		name    string
+		changes []Change
+		want    Bump
+	}{
+		{"no changes", nil, BumpNone},
+		{"patch", []Change{change(Patch)}, BumpPatch},
+		{"additive", []Change{change(Patch), change(Additive)}, BumpMinor},
+		{"breaking", []Change{change(Additive), change(Breaking), change(Patch)}, BumpMajor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (ChangeSet{Changes: tt.changes}).Bump(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
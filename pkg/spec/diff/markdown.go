@@ -0,0 +1,70 @@
+package spec_diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+var impactSections = []struct {
+	impact Impact
+	title  string
+}{
+	{Breaking, "Breaking changes"},
+	{Additive, "Additive changes"},
+	{Patch, "Documentation changes"},
+}
+
+// Markdown renders the change set as a human-readable changelog.
+func (cs ChangeSet) Markdown() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# Bot API changes: %s → %s\n\n", cs.OldVersion, cs.NewVersion))
+
+	if cs.IsEmpty() {
+		sb.WriteString("No changes detected.\n")
+
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("Suggested version bump: **%s**\n", cs.Bump()))
+
+	for _, section := range impactSections {
+		changes := cs.Filter(section.impact)
+		if len(changes) == 0 {
+			continue
+		}
+
+		sb.WriteString(fmt.Sprintf("\n## %s\n\n", section.title))
+		for _, c := range changes {
+			sb.WriteString(fmt.Sprintf("- `%s`: %s\n", c.Path, describe(c)))
+		}
+	}
+
+	return sb.String()
+}
+
+func describe(c Change) string {
+	if c.Attribute == "" {
+		return fmt.Sprintf("%s %s", c.Element, c.Kind)
+	}
+
+	switch c.Attribute {
+	case "description":
+		return "description changed"
+	case "types", "returns", "parents", "children", "enum", "argumentGroups", "conditionalReturns":
+		if c.Kind == Added {
+			return fmt.Sprintf("`%s` added to %s", c.New, c.Attribute)
+		}
+
+		return fmt.Sprintf("`%s` removed from %s", c.Old, c.Attribute)
+	}
+
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("%s set to `%s`", c.Attribute, c.New)
+	case Removed:
+		return fmt.Sprintf("%s `%s` removed", c.Attribute, c.Old)
+	}
+
+	return fmt.Sprintf("%s changed from `%s` to `%s`", c.Attribute, c.Old, c.New)
+}
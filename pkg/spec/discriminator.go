@@ -0,0 +1,156 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TgTypeSpecDiscriminator describes the property of the polymorphic type which value tells what child the object is,
+// e.g. `status` for ChatMember, which is `creator` for ChatMemberOwner.
+type TgTypeSpecDiscriminator struct {
+	propertyName string
+	mapping      map[string]*TgTypeSpec
+}
+
+func (tsd TgTypeSpecDiscriminator) GetPropertyName() string {
+	return tsd.propertyName
+}
+
+// GetMapping returns children of the type by values of the discriminator property.
+func (tsd TgTypeSpecDiscriminator) GetMapping() map[string]*TgTypeSpec {
+	return tsd.mapping
+}
+
+// GetChild returns the child of the type which has the provided value of the discriminator property.
+func (tsd TgTypeSpecDiscriminator) GetChild(value string) (*TgTypeSpec, bool) {
+	child, exists := tsd.mapping[value]
+
+	return child, exists
+}
+
+// GetDiscriminator returns the discriminator of the polymorphic type,
+// or nil if the type has no children or the children can't be told apart by a value of one property.
+// It's resolved from the current children on every call, so callers which need it several times should keep the result.
+func (tts TgTypeSpec) GetDiscriminator() *TgTypeSpecDiscriminator {
+	discriminator, _ := resolveDiscriminator(tts)
+
+	return discriminator
+}
+
+// duplicateValuesError means that children share the value of the discriminator property, so there is no discriminator.
+// The official doc has such children, e.g. InlineQueryResultPhoto and InlineQueryResultCachedPhoto, so it isn't fatal.
+type duplicateValuesError struct {
+	message string
+}
+
+func (e duplicateValuesError) Error() string {
+	return e.message
+}
+
+// resolveDiscriminator finds the property which has a predefined value in every child of the type.
+// If there are several such properties, the one with unique values is preferred, then `type`, then the first by name.
+// It's an error when some children have predefined values, but none of the properties is shared by all children.
+func resolveDiscriminator(tts TgTypeSpec) (*TgTypeSpecDiscriminator, error) {
+	children := tts.GetChildren()
+	if len(children) == 0 {
+		return nil, nil
+	}
+
+	candidates := make(map[string]map[string][]*TgTypeSpec)
+	hasValues := false
+	for i, child := range children {
+		values := predefinedValues(child)
+		hasValues = hasValues || len(values) > 0
+
+		for name := range candidates {
+			if _, exists := values[name]; !exists {
+				delete(candidates, name)
+			}
+		}
+
+		for name, value := range values {
+			if i == 0 {
+				candidates[name] = make(map[string][]*TgTypeSpec)
+			}
+			if mapping, exists := candidates[name]; exists {
+				mapping[value] = append(mapping[value], child)
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		if hasValues {
+			return nil, errors.New(fmt.Sprintf("object %s has children without a common property with predefined values", tts.GetName()))
+		}
+
+		return nil, nil
+	}
+
+	var names []string
+	for name := range candidates {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == "type") != (names[j] == "type") {
+			return names[i] == "type"
+		}
+
+		return names[i] < names[j]
+	})
+
+	var unique []string
+	for _, name := range names {
+		if len(candidates[name]) == len(children) {
+			unique = append(unique, name)
+		}
+	}
+
+	if len(unique) == 0 {
+		return nil, newDuplicateValuesError(tts, names[0], candidates[names[0]])
+	}
+	names = unique
+
+	discriminator := &TgTypeSpecDiscriminator{propertyName: names[0], mapping: make(map[string]*TgTypeSpec)}
+	for value, types := range candidates[names[0]] {
+		discriminator.mapping[value] = types[0]
+	}
+
+	return discriminator, nil
+}
+
+// newDuplicateValuesError names children which share the value of the property, the first value by name is reported.
+func newDuplicateValuesError(tts TgTypeSpec, name string, mapping map[string][]*TgTypeSpec) error {
+	var values []string
+	for value, types := range mapping {
+		if len(types) > 1 {
+			values = append(values, value)
+		}
+	}
+	sort.Strings(values)
+
+	var childNames []string
+	for _, t := range mapping[values[0]] {
+		childNames = append(childNames, t.GetName())
+	}
+
+	return duplicateValuesError{fmt.Sprintf(
+		"object %s has children %s with the same value '%s' of property %s",
+		tts.GetName(),
+		strings.Join(childNames, ", "),
+		values[0],
+		name,
+	)}
+}
+
+func predefinedValues(t *TgTypeSpec) map[string]string {
+	values := make(map[string]string)
+	for _, p := range t.GetProperties() {
+		if p.GetPredefinedValue() != nil {
+			values[p.GetName()] = string(*p.GetPredefinedValue())
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,101 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+// newPolymorphicType creates InlineQueryResult with children which have the provided values of the `type` property.
+func newPolymorphicType(t *testing.T, values map[string]string) (*spec.TgTypeSpec, map[string]*spec.TgTypeSpecProperty) {
+	t.Helper()
+
+	parent, err := spec.NewTgTypeSpec("inline-mode", "InlineQueryResult", spectest.Link("InlineQueryResult"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	properties := make(map[string]*spec.TgTypeSpecProperty)
+	for name, value := range values {
+		child, _ := spec.NewTgTypeSpec("inline-mode", name, spectest.Link(name))
+		p, _ := spec.NewTgTypeSpecProperty("type")
+		v := spec.TgTypeSpecPropertyValue(value)
+		p.SetPredefinedValue(&v)
+		child.AddProperty(p)
+
+		spectest.AddChildren(parent, child)
+		properties[name] = p
+	}
+
+	return parent, properties
+}
+
+func TestDuplicateDiscriminatorValues(t *testing.T) {
+	parent, _ := newPolymorphicType(t, map[string]string{
+		"InlineQueryResultPhoto":       "photo",
+		"InlineQueryResultCachedPhoto": "photo",
+		"InlineQueryResultGif":         "gif",
+	})
+
+	if d := parent.GetDiscriminator(); d != nil {
+		t.Errorf("expected no discriminator, got %s", d.GetPropertyName())
+	}
+
+	as := spectest.New(t, func(as *spec.ApiSpec) error {
+		as.AddType(parent)
+		for _, child := range parent.GetChildren() {
+			as.AddType(child)
+		}
+
+		return nil
+	})
+
+	var found bool
+	for _, d := range as.Diagnose() {
+		switch d.Code {
+		case spec.CodeDuplicateDiscriminatorValue:
+			found = true
+			if d.Severity != spec.SeverityWarning {
+				t.Errorf("expected the warning, got %s: %s", d.Severity, d.Message)
+			}
+		case spec.CodeInconsistentDiscriminator:
+			t.Errorf("unexpected diagnostic: %s", d)
+		}
+	}
+	if !found {
+		t.Errorf("expected the %s diagnostic", spec.CodeDuplicateDiscriminatorValue)
+	}
+}
+
+func TestDiscriminatorFollowsPredefinedValues(t *testing.T) {
+	parent, properties := newPolymorphicType(t, map[string]string{
+		"InlineQueryResultPhoto":       "photo",
+		"InlineQueryResultCachedPhoto": "photo",
+	})
+
+	if d := parent.GetDiscriminator(); d != nil {
+		t.Fatalf("expected no discriminator, got %s", d.GetPropertyName())
+	}
+
+	value := spec.TgTypeSpecPropertyValue("cached_photo")
+	properties["InlineQueryResultCachedPhoto"].SetPredefinedValue(&value)
+
+	d := parent.GetDiscriminator()
+	if d == nil {
+		t.Fatal("expected the discriminator after changing the value")
+	}
+	if child, exists := d.GetChild("cached_photo"); !exists || child.GetName() != "InlineQueryResultCachedPhoto" {
+		t.Errorf("unexpected mapping %v", d.GetMapping())
+	}
+}
+
+func TestDiagnoseTypeWithoutConstructor(t *testing.T) {
+	as := spectest.New(t, func(as *spec.ApiSpec) error {
+		return as.AddType(&spec.TgTypeSpec{})
+	})
+
+	if len(as.Diagnose()) == 0 {
+		t.Error("expected diagnostics of the empty type")
+	}
+}
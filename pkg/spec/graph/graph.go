@@ -0,0 +1,377 @@
+// Package spec_graph indexes references between elements of the spec, e.g. to find what to regenerate when a type changes
+// or which types can be pruned as unused. The spec package can't depend on the graph, so its own operations (Subset,
+// PruneDataTypes) walk references without it.
+package spec_graph
+
+import (
+	"sort"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+type NodeKind string
+
+const (
+	NodeType     NodeKind = "type"
+	NodeMethod   NodeKind = "method"
+	NodeProperty NodeKind = "property"
+	NodeArgument NodeKind = "argument"
+)
+
+// Node is an element of the spec identified by its path, e.g. `types.Message.properties.chat`.
+type Node struct {
+	Kind NodeKind
+	Path string
+}
+
+func TypeNode(name string) Node {
+	return Node{NodeType, "types." + name}
+}
+
+func MethodNode(name string) Node {
+	return Node{NodeMethod, "methods." + name}
+}
+
+func PropertyNode(typeName, name string) Node {
+	return Node{NodeProperty, "types." + typeName + ".properties." + name}
+}
+
+func ArgumentNode(methodName, name string) Node {
+	return Node{NodeArgument, "methods." + methodName + ".arguments." + name}
+}
+
+type EdgeKind string
+
+const (
+	// EdgeProperty goes from the type to its property.
+	EdgeProperty EdgeKind = "property"
+	// EdgeArgument goes from the method to its argument.
+	EdgeArgument EdgeKind = "argument"
+	// EdgeDataType goes from the property or the argument to the type of its value, including array elements and union members.
+	EdgeDataType EdgeKind = "dataType"
+	// EdgeReturn goes from the method to the type of its result.
+	EdgeReturn EdgeKind = "return"
+	// EdgeChild goes from the polymorphic type to its child.
+	EdgeChild EdgeKind = "child"
+)
+
+type Edge struct {
+	Kind EdgeKind
+	From Node
+	To   Node
+}
+
+// Graph is the index of references between elements of the spec. It isn't updated when the spec changes.
+type Graph struct {
+	forward map[Node][]Edge
+	reverse map[Node][]Edge
+	types   []string
+	methods []string
+}
+
+func New(as *spec.ApiSpec) *Graph {
+	g := &Graph{
+		forward: make(map[Node][]Edge),
+		reverse: make(map[Node][]Edge),
+	}
+
+	// Elements are visited in the same order every time, so the order of edges is stable.
+	for _, t := range as.GetOrderedTypes(spec.OrderAlphabetical) {
+		g.types = append(g.types, t.GetName())
+		for _, child := range t.GetChildren() {
+			g.add(EdgeChild, TypeNode(t.GetName()), TypeNode(child.GetName()))
+		}
+
+		for _, p := range t.GetProperties() {
+			property := PropertyNode(t.GetName(), p.GetName())
+			g.add(EdgeProperty, TypeNode(t.GetName()), property)
+			g.addDataTypes(EdgeDataType, property, p.GetDataTypes())
+		}
+	}
+
+	for _, m := range as.GetOrderedMethods(spec.OrderAlphabetical) {
+		g.methods = append(g.methods, m.GetName())
+		method := MethodNode(m.GetName())
+		g.addDataTypes(EdgeReturn, method, m.GetReturnTypes())
+
+		for _, a := range m.GetArguments() {
+			argument := ArgumentNode(m.GetName(), a.GetName())
+			g.add(EdgeArgument, method, argument)
+			g.addDataTypes(EdgeDataType, argument, a.GetDataTypes())
+		}
+	}
+
+	return g
+}
+
+func (g *Graph) add(kind EdgeKind, from, to Node) {
+	for _, e := range g.forward[from] {
+		if e.Kind == kind && e.To == to {
+			return
+		}
+	}
+
+	edge := Edge{kind, from, to}
+	g.forward[from] = append(g.forward[from], edge)
+	g.reverse[to] = append(g.reverse[to], edge)
+}
+
+func (g *Graph) addDataTypes(kind EdgeKind, from Node, dataTypes []spec.DataTypeDefinition) {
+	for _, dt := range dataTypes {
+		for _, t := range spec.ReferencedTypes(dt) {
+			g.add(kind, from, TypeNode(t.GetName()))
+		}
+	}
+}
+
+// Edges returns the edges going from the node.
+func (g Graph) Edges(n Node) []Edge {
+	return g.forward[n]
+}
+
+// ReverseEdges returns the edges coming to the node.
+func (g Graph) ReverseEdges(n Node) []Edge {
+	return g.reverse[n]
+}
+
+// ReturnedBy returns names of the methods which can return the type.
+func (g Graph) ReturnedBy(typeName string) []string {
+	var methods []string
+	for _, e := range g.reverse[TypeNode(typeName)] {
+		if e.Kind == EdgeReturn {
+			methods = append(methods, nameOf(e.From))
+		}
+	}
+	sort.Strings(methods)
+
+	return methods
+}
+
+// ContainedBy returns names of the types which have a property of the type.
+func (g Graph) ContainedBy(typeName string) []string {
+	return g.owners(typeName, NodeProperty, EdgeProperty)
+}
+
+// AcceptedBy returns names of the methods which have an argument of the type.
+func (g Graph) AcceptedBy(typeName string) []string {
+	return g.owners(typeName, NodeArgument, EdgeArgument)
+}
+
+func (g Graph) owners(typeName string, kind NodeKind, ownerEdge EdgeKind) []string {
+	set := make(map[string]bool)
+	for _, e := range g.reverse[TypeNode(typeName)] {
+		if e.Kind != EdgeDataType || e.From.Kind != kind {
+			continue
+		}
+
+		for _, owner := range g.reverse[e.From] {
+			if owner.Kind == ownerEdge {
+				set[nameOf(owner.From)] = true
+			}
+		}
+	}
+
+	return sortedKeys(set)
+}
+
+// Closure returns the nodes reachable from the provided ones (the provided nodes aren't included unless they're reachable).
+// With reverse set, edges are followed backwards, so the result is the nodes which depend on the provided ones.
+func (g Graph) Closure(nodes []Node, reverse bool) []Node {
+	edges := g.forward
+	if reverse {
+		edges = g.reverse
+	}
+
+	visited := make(map[Node]bool)
+	queue := append([]Node{}, nodes...)
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		for _, e := range edges[n] {
+			next := e.To
+			if reverse {
+				next = e.From
+			}
+
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	result := make([]Node, 0, len(visited))
+	for n := range visited {
+		result = append(result, n)
+	}
+	sortNodes(result)
+
+	return result
+}
+
+// Dependencies returns names of the types which the type refers to, directly or transitively.
+func (g Graph) Dependencies(typeName string) []string {
+	return namesOf(g.Closure([]Node{TypeNode(typeName)}, false), NodeType)
+}
+
+// Dependents returns the types and methods which refer to the type, directly or transitively.
+// It's the set of elements to regenerate when the type changes.
+func (g Graph) Dependents(typeName string) []Node {
+	var dependents []Node
+	for _, n := range g.Closure([]Node{TypeNode(typeName)}, true) {
+		if n.Kind == NodeType || n.Kind == NodeMethod {
+			dependents = append(dependents, n)
+		}
+	}
+
+	return dependents
+}
+
+// Unused returns names of the types which can't be reached from any method.
+func (g Graph) Unused() []string {
+	roots := make([]Node, len(g.methods))
+	for i, name := range g.methods {
+		roots[i] = MethodNode(name)
+	}
+
+	used := make(map[string]bool)
+	for _, name := range namesOf(g.Closure(roots, false), NodeType) {
+		used[name] = true
+	}
+
+	var unused []string
+	for _, name := range g.types {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+
+	return unused
+}
+
+// Cycles returns groups of types which refer to each other through properties or children, e.g. `Message` via `reply_to_message`.
+// Every group is sorted by name; a group of one type means the type refers to itself.
+func (g Graph) Cycles() [][]string {
+	t := &tarjan{g: g, index: make(map[string]int), low: make(map[string]int), onStack: make(map[string]bool)}
+	for _, name := range g.types {
+		if _, visited := t.index[name]; !visited {
+			t.visit(name)
+		}
+	}
+
+	sort.Slice(t.cycles, func(i, j int) bool {
+		return t.cycles[i][0] < t.cycles[j][0]
+	})
+
+	return t.cycles
+}
+
+// typeSuccessors returns names of the types which the type refers to directly.
+func (g Graph) typeSuccessors(typeName string) []string {
+	set := make(map[string]bool)
+	for _, e := range g.forward[TypeNode(typeName)] {
+		switch e.Kind {
+		case EdgeChild:
+			set[nameOf(e.To)] = true
+		case EdgeProperty:
+			for _, pe := range g.forward[e.To] {
+				set[nameOf(pe.To)] = true
+			}
+		}
+	}
+
+	return sortedKeys(set)
+}
+
+// tarjan finds strongly connected components of the type graph.
+type tarjan struct {
+	g       Graph
+	counter int
+	index   map[string]int
+	low     map[string]int
+	stack   []string
+	onStack map[string]bool
+	cycles  [][]string
+}
+
+func (t *tarjan) visit(name string) {
+	t.index[name], t.low[name] = t.counter, t.counter
+	t.counter++
+	t.stack = append(t.stack, name)
+	t.onStack[name] = true
+
+	selfLoop := false
+	for _, next := range t.g.typeSuccessors(name) {
+		if next == name {
+			selfLoop = true
+		}
+
+		if _, visited := t.index[next]; !visited {
+			t.visit(next)
+			if t.low[next] < t.low[name] {
+				t.low[name] = t.low[next]
+			}
+		} else if t.onStack[next] && t.index[next] < t.low[name] {
+			t.low[name] = t.index[next]
+		}
+	}
+
+	if t.low[name] != t.index[name] {
+		return
+	}
+
+	var component []string
+	for {
+		last := t.stack[len(t.stack)-1]
+		t.stack = t.stack[:len(t.stack)-1]
+		t.onStack[last] = false
+		component = append(component, last)
+		if last == name {
+			break
+		}
+	}
+
+	if len(component) > 1 || selfLoop {
+		sort.Strings(component)
+		t.cycles = append(t.cycles, component)
+	}
+}
+
+// nameOf returns the name of the element from its path, e.g. `chat` for `types.Message.properties.chat`.
+func nameOf(n Node) string {
+	for i := len(n.Path) - 1; i >= 0; i-- {
+		if n.Path[i] == '.' {
+			return n.Path[i+1:]
+		}
+	}
+
+	return n.Path
+}
+
+func namesOf(nodes []Node, kind NodeKind) []string {
+	var names []string
+	for _, n := range nodes {
+		if n.Kind == kind {
+			names = append(names, nameOf(n))
+		}
+	}
+
+	return names
+}
+
+func sortNodes(nodes []Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Path < nodes[j].Path
+	})
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,144 @@
+package spec_graph
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+// newGraph builds the graph of the spec where `Message` refers to `Chat`, to itself and to `MaybeInaccessibleMessage`,
+// which is the parent of `Message` and `InaccessibleMessage`. `Orphan` isn't used by anything.
+func newGraph(t *testing.T) *Graph {
+	t.Helper()
+
+	return New(spectest.New(t, func(as *spec.ApiSpec) error {
+		spectest.AddType(as, "Chat", "id int64")
+		spectest.AddType(as, "Orphan", "chat Chat")
+		message := spectest.AddType(as, "Message", "chat Chat", "reply_to_message Message", "pinned_message MaybeInaccessibleMessage")
+		inaccessible := spectest.AddType(as, "InaccessibleMessage", "chat Chat")
+		spectest.AddChildren(spectest.AddType(as, "MaybeInaccessibleMessage"), message, inaccessible)
+
+		spectest.AddMethod(as, "sendMessage", "Message", "chat_id int64|string")
+		spectest.AddMethod(as, "getChat", "Chat")
+		spectest.AddMethod(as, "forwardMessages", "true", "messages array<Message>")
+
+		return nil
+	}))
+}
+
+func TestClosure(t *testing.T) {
+	g := newGraph(t)
+
+	tests := []struct {
+		name    string
+		nodes   []Node
+		reverse bool
+		want    []Node
+	}{
+		{
+			name:  "forward from the method",
+			nodes: []Node{MethodNode("getChat")},
+			want:  []Node{TypeNode("Chat"), PropertyNode("Chat", "id")},
+		},
+		{
+			name:    "reverse from the type",
+			nodes:   []Node{TypeNode("InaccessibleMessage")},
+			reverse: true,
+			want: []Node{
+				MethodNode("forwardMessages"),
+				ArgumentNode("forwardMessages", "messages"),
+				MethodNode("sendMessage"),
+				TypeNode("MaybeInaccessibleMessage"),
+				TypeNode("Message"),
+				PropertyNode("Message", "pinned_message"),
+				PropertyNode("Message", "reply_to_message"),
+			},
+		},
+		{
+			name:  "type without references",
+			nodes: []Node{TypeNode("MaybeInaccessibleMessage")},
+			want: []Node{
+				TypeNode("Chat"),
+				PropertyNode("Chat", "id"),
+				TypeNode("InaccessibleMessage"),
+				PropertyNode("InaccessibleMessage", "chat"),
+				TypeNode("MaybeInaccessibleMessage"),
+				TypeNode("Message"),
+				PropertyNode("Message", "chat"),
+				PropertyNode("Message", "pinned_message"),
+				PropertyNode("Message", "reply_to_message"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.Closure(tt.nodes, tt.reverse); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueries(t *testing.T) {
+	g := newGraph(t)
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"unused", g.Unused(), []string{"Orphan"}},
+		{"dependencies", g.Dependencies("InaccessibleMessage"), []string{"Chat"}},
+		{"dependents", g.Dependents("Chat"), []Node{
+			MethodNode("forwardMessages"),
+			MethodNode("getChat"),
+			MethodNode("sendMessage"),
+			TypeNode("InaccessibleMessage"),
+			TypeNode("MaybeInaccessibleMessage"),
+			TypeNode("Message"),
+			TypeNode("Orphan"),
+		}},
+		{"returned by", g.ReturnedBy("Message"), []string{"sendMessage"}},
+		{"contained by", g.ContainedBy("Chat"), []string{"InaccessibleMessage", "Message", "Orphan"}},
+		{"accepted by", g.AcceptedBy("Message"), []string{"forwardMessages"}},
+		{"cycles", g.Cycles(), [][]string{{"MaybeInaccessibleMessage", "Message"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEdgesOrder(t *testing.T) {
+	g := newGraph(t)
+
+	wantEdges := []Edge{
+		{EdgeProperty, TypeNode("Message"), PropertyNode("Message", "chat")},
+		{EdgeProperty, TypeNode("Message"), PropertyNode("Message", "reply_to_message")},
+		{EdgeProperty, TypeNode("Message"), PropertyNode("Message", "pinned_message")},
+	}
+	wantReverseEdges := []Edge{
+		{EdgeDataType, PropertyNode("InaccessibleMessage", "chat"), TypeNode("Chat")},
+		{EdgeDataType, PropertyNode("Message", "chat"), TypeNode("Chat")},
+		{EdgeDataType, PropertyNode("Orphan", "chat"), TypeNode("Chat")},
+		{EdgeReturn, MethodNode("getChat"), TypeNode("Chat")},
+	}
+
+	// Maps of the spec are iterated in random order, so the graph is built several times.
+	for i := 0; i < 10; i++ {
+		if got := g.Edges(TypeNode("Message")); !reflect.DeepEqual(got, wantEdges) {
+			t.Fatalf("got edges %v, want %v", got, wantEdges)
+		}
+		if got := g.ReverseEdges(TypeNode("Chat")); !reflect.DeepEqual(got, wantReverseEdges) {
+			t.Fatalf("got reverse edges %v, want %v", got, wantReverseEdges)
+		}
+		g = newGraph(t)
+	}
+}
@@ -0,0 +1,246 @@
+package spec_history
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	datasource_json "github.com/alserom/tg-bot-api-spec/pkg/datasource/json"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	spec_diff "github.com/alserom/tg-bot-api-spec/pkg/spec/diff"
+)
+
+// History is the series of spec snapshots sorted by Bot API version.
+type History struct {
+	snapshots []*spec.ApiSpec
+}
+
+func (h History) GetVersions() []string {
+	versions := make([]string, len(h.snapshots))
+	for i, s := range h.snapshots {
+		versions[i] = s.GetVersion()
+	}
+
+	return versions
+}
+
+// Annotate sets versions of every type, property, method and argument of the spec.
+// The spec is put into the series in place of the snapshot of the same version, so it can be newer or older than all the snapshots.
+func (h History) Annotate(as *spec.ApiSpec) error {
+	if as.GetVersion() == "" {
+		return errors.New("version of the annotated spec not set")
+	}
+
+	timeline := make([]*spec.ApiSpec, 0, len(h.snapshots)+1)
+	current := -1
+	for _, s := range h.snapshots {
+		cmp := CompareVersions(s.GetVersion(), as.GetVersion())
+		if current < 0 && cmp >= 0 {
+			current = len(timeline)
+			timeline = append(timeline, as)
+		}
+		if cmp != 0 {
+			timeline = append(timeline, s)
+		}
+	}
+	if current < 0 {
+		current = len(timeline)
+		timeline = append(timeline, as)
+	}
+
+	presence := make([]map[string]bool, len(timeline))
+	for i, s := range timeline {
+		presence[i] = elementPaths(s)
+	}
+
+	changes := make([]map[string]bool, len(timeline))
+	for i := 1; i < len(timeline); i++ {
+		changes[i] = changedPaths(spec_diff.Compare(timeline[i-1], timeline[i]))
+	}
+
+	versionsOf := func(path string) *spec.VersionHistory {
+		since := current
+		for since > 0 && presence[since-1][path] {
+			since--
+		}
+
+		lastChanged := since
+		for i := current; i > since; i-- {
+			if changes[i][path] {
+				lastChanged = i
+				break
+			}
+		}
+
+		history := &spec.VersionHistory{
+			Since:       timeline[since].GetVersion(),
+			LastChanged: timeline[lastChanged].GetVersion(),
+		}
+		for i := current + 1; i < len(timeline); i++ {
+			if !presence[i][path] {
+				history.Until = timeline[i].GetVersion()
+				break
+			}
+		}
+
+		return history
+	}
+
+	for _, t := range as.GetTypes() {
+		t.SetHistory(versionsOf(typePath(t.GetName())))
+		for _, p := range t.GetProperties() {
+			p.SetHistory(versionsOf(propertyPath(t.GetName(), p.GetName())))
+		}
+	}
+
+	for _, m := range as.GetMethods() {
+		m.SetHistory(versionsOf(methodPath(m.GetName())))
+		for _, a := range m.GetArguments() {
+			a.SetHistory(versionsOf(argumentPath(m.GetName(), a.GetName())))
+		}
+	}
+
+	return nil
+}
+
+func New(snapshots ...*spec.ApiSpec) (*History, error) {
+	sorted := append([]*spec.ApiSpec{}, snapshots...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return CompareVersions(sorted[i].GetVersion(), sorted[j].GetVersion()) < 0
+	})
+
+	for i, s := range sorted {
+		if s.GetVersion() == "" {
+			return nil, errors.New("version of the snapshot not set")
+		}
+		if i > 0 && CompareVersions(sorted[i-1].GetVersion(), s.GetVersion()) == 0 {
+			return nil, errors.New(fmt.Sprintf("several snapshots of version %s", s.GetVersion()))
+		}
+	}
+
+	return &History{sorted}, nil
+}
+
+// Load loads snapshots written by the JSON exporter.
+func Load(paths ...string) (*History, error) {
+	var snapshots []*spec.ApiSpec
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+
+		datasource, err := datasource_json.NewDatasourceJson(absPath)
+		if err != nil {
+			return nil, errors.New(path + ": " + err.Error())
+		}
+
+		snapshot, err := spec.NewApiSpec(datasource)
+		if err != nil {
+			return nil, errors.New(path + ": " + err.Error())
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return New(snapshots...)
+}
+
+// CompareVersions compares versions like `6.9` and `6.10` part by part.
+// A part with a suffix like `0-beta` is a pre-release, so it's lower than the part of the same number without the suffix.
+func CompareVersions(a, b string) int {
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		aPart, bPart := "0", "0"
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+
+		aNum, aSuffix := splitVersionPart(aPart)
+		bNum, bSuffix := splitVersionPart(bPart)
+		switch {
+		case aNum != bNum:
+			if aNum < bNum {
+				return -1
+			}
+			return 1
+		case aSuffix == bSuffix:
+			continue
+		case aSuffix == "":
+			return 1
+		case bSuffix == "":
+			return -1
+		default:
+			return strings.Compare(aSuffix, bSuffix)
+		}
+	}
+
+	return 0
+}
+
+// splitVersionPart splits the part into the leading number and the rest, the number is 0 when the part doesn't start with digits.
+func splitVersionPart(part string) (int, string) {
+	digits := len(part) - len(strings.TrimLeft(part, "0123456789"))
+	num, _ := strconv.Atoi(part[:digits])
+
+	return num, part[digits:]
+}
+
+func elementPaths(as *spec.ApiSpec) map[string]bool {
+	paths := make(map[string]bool)
+	for _, t := range as.GetTypes() {
+		paths[typePath(t.GetName())] = true
+		for _, p := range t.GetProperties() {
+			paths[propertyPath(t.GetName(), p.GetName())] = true
+		}
+	}
+
+	for _, m := range as.GetMethods() {
+		paths[methodPath(m.GetName())] = true
+		for _, a := range m.GetArguments() {
+			paths[argumentPath(m.GetName(), a.GetName())] = true
+		}
+	}
+
+	return paths
+}
+
+// changedPaths returns paths of the elements which were changed themselves or which properties or arguments were changed.
+// Elements which were only added or removed aren't changed, changes of their attributes have the attribute set.
+func changedPaths(cs *spec_diff.ChangeSet) map[string]bool {
+	paths := make(map[string]bool)
+	for _, c := range cs.Changes {
+		if c.Kind == spec_diff.Changed || c.Attribute != "" {
+			paths[c.Path] = true
+		}
+
+		// Paths look like `types.Message.properties.chat`, so the first two parts are the path of the owner.
+		if parts := strings.SplitN(c.Path, ".", 3); len(parts) == 3 {
+			paths[parts[0]+"."+parts[1]] = true
+		}
+	}
+
+	return paths
+}
+
+func typePath(name string) string {
+	return "types." + name
+}
+
+func propertyPath(typeName, name string) string {
+	return typePath(typeName) + ".properties." + name
+}
+
+func methodPath(name string) string {
+	return "methods." + name
+}
+
+func argumentPath(methodName, name string) string {
+	return methodPath(methodName) + ".arguments." + name
+}
@@ -0,0 +1,96 @@
+package spec_history
+
+import (
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+// snapshot builds the spec of the version with the `Chat` type which properties have the data types.
+func snapshot(t *testing.T, version string, properties map[string]string) *spec.ApiSpec {
+	t.Helper()
+
+	return spectest.New(t, func(as *spec.ApiSpec) error {
+		as.SetVersion(version)
+
+		var definitions []string
+		for name, definition := range properties {
+			definitions = append(definitions, name+" "+definition)
+		}
+		spectest.AddType(as, "Chat", definitions...)
+
+		return nil
+	})
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"6.9", "6.10", -1},
+		{"7.0", "7", 0},
+		{"7.1", "7.0", 1},
+		{"7.0", "7.0-beta", 1},
+		{"7.0-beta", "7.0", -1},
+		{"7.0-beta", "7", -1},
+		{"7.0-beta", "7.0-rc", -1},
+		{"7.0-beta", "7.0-beta", 0},
+		{"6.10-beta", "6.9", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			if got := CompareVersions(tt.a, tt.b); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRejectsSameVersions(t *testing.T) {
+	if _, err := New(snapshot(t, "7.0", nil), snapshot(t, "7", nil)); err == nil {
+		t.Error("expected the error")
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	history, err := New(
+		snapshot(t, "7.2", map[string]string{"id": "int64"}),
+		snapshot(t, "6.0", map[string]string{"id": "int32"}),
+		snapshot(t, "7.0", map[string]string{"id": "int64", "title": "string"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	as := snapshot(t, "7.1", map[string]string{"id": "int64", "title": "string"})
+	if err := history.Annotate(as); err != nil {
+		t.Fatal(err)
+	}
+
+	chat, _ := as.GetType("Chat")
+	properties := make(map[string]*spec.TgTypeSpecProperty)
+	for _, p := range chat.GetProperties() {
+		properties[p.GetName()] = p
+	}
+
+	tests := []struct {
+		name string
+		got  *spec.VersionHistory
+		want spec.VersionHistory
+	}{
+		{"type", chat.GetHistory(), spec.VersionHistory{Since: "6.0", LastChanged: "7.0"}},
+		{"changed property", properties["id"].GetHistory(), spec.VersionHistory{Since: "6.0", LastChanged: "7.0"}},
+		{"removed property", properties["title"].GetHistory(), spec.VersionHistory{Since: "7.0", LastChanged: "7.0", Until: "7.2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got == nil || *tt.got != tt.want {
+				t.Errorf("got %+v, want %+v", tt.got, tt.want)
+			}
+		})
+	}
+}
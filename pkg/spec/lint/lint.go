@@ -0,0 +1,162 @@
+package spec_lint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+// Rule checks the spec and reports its findings as diagnostics with the code equal to the rule name.
+type Rule interface {
+	// Name is the stable identifier of the rule, which is used in configs and reports.
+	Name() string
+	Description() string
+	// Severity is the default severity of the rule findings.
+	Severity() spec.Severity
+	Check(as *spec.ApiSpec) spec.Diagnostics
+}
+
+// Registry holds rules by their names.
+type Registry struct {
+	rules map[string]Rule
+}
+
+func NewRegistry(rules ...Rule) (*Registry, error) {
+	r := &Registry{rules: make(map[string]Rule)}
+	for _, rule := range rules {
+		if err := r.Register(rule); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// DefaultRegistry returns the registry with all the built-in rules.
+func DefaultRegistry() *Registry {
+	r, _ := NewRegistry(builtinRules()...)
+
+	return r
+}
+
+func (r *Registry) Register(rule Rule) error {
+	if rule == nil {
+		return errors.New("rule is nil")
+	}
+
+	if _, exists := r.rules[rule.Name()]; exists {
+		return errors.New(fmt.Sprintf("rule %s is already registered", rule.Name()))
+	}
+
+	r.rules[rule.Name()] = rule
+
+	return nil
+}
+
+func (r Registry) GetRule(name string) (Rule, bool) {
+	rule, exists := r.rules[name]
+
+	return rule, exists
+}
+
+// GetRules returns the registered rules sorted by name.
+func (r Registry) GetRules() []Rule {
+	rules := make([]Rule, 0, len(r.rules))
+	for _, rule := range r.rules {
+		rules = append(rules, rule)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Name() < rules[j].Name()
+	})
+
+	return rules
+}
+
+// Config enables and disables rules and overrides their severities. Rules are enabled by default.
+// The severity is overridden for all the findings of the rule, including self-check findings of any severity.
+type Config struct {
+	Disabled []string                 `json:"disabled,omitempty"`
+	Severity map[string]spec.Severity `json:"severity,omitempty"`
+}
+
+// LoadConfig reads the config from the JSON file, e.g. `{"disabled": ["unreferenced-type"], "severity": {"untyped-array": "error"}}`.
+func LoadConfig(path string) (Config, error) {
+	var config Config
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+
+	if err := json.Unmarshal(content, &config); err != nil {
+		return config, errors.New(path + ": " + err.Error())
+	}
+
+	return config, nil
+}
+
+type Linter struct {
+	rules    []Rule
+	severity map[string]spec.Severity
+}
+
+// NewLinter creates the linter with the enabled rules of the registry. Unknown rule names in the config are an error.
+func NewLinter(registry *Registry, config Config) (*Linter, error) {
+	disabled := make(map[string]bool)
+	for _, name := range config.Disabled {
+		if _, exists := registry.GetRule(name); !exists {
+			return nil, errors.New("unknown rule: " + name)
+		}
+		disabled[name] = true
+	}
+
+	for name, severity := range config.Severity {
+		if _, exists := registry.GetRule(name); !exists {
+			return nil, errors.New("unknown rule: " + name)
+		}
+		if severity != spec.SeverityError && severity != spec.SeverityWarning && severity != spec.SeverityInfo {
+			return nil, errors.New(fmt.Sprintf("unknown severity of rule %s: %s", name, severity))
+		}
+	}
+
+	l := &Linter{severity: config.Severity}
+	for _, rule := range registry.GetRules() {
+		if !disabled[rule.Name()] {
+			l.rules = append(l.rules, rule)
+		}
+	}
+
+	return l, nil
+}
+
+func (l Linter) GetRules() []Rule {
+	return l.rules
+}
+
+// Lint runs the enabled rules and returns their findings sorted by element path.
+func (l Linter) Lint(as *spec.ApiSpec) spec.Diagnostics {
+	var diagnostics spec.Diagnostics
+	for _, rule := range l.rules {
+		for _, d := range rule.Check(as) {
+			if severity, exists := l.severity[rule.Name()]; exists {
+				d.Severity = severity
+			}
+			diagnostics = append(diagnostics, d)
+		}
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].Path != diagnostics[j].Path {
+			return diagnostics[i].Path < diagnostics[j].Path
+		}
+
+		return diagnostics[i].Code < diagnostics[j].Code
+	})
+
+	return diagnostics
+}
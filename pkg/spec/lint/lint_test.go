@@ -0,0 +1,105 @@
+package spec_lint
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+// mixedRule reports findings of all the severities, like the self-check rule does.
+func mixedRule() Rule {
+	return rule{
+		name:        "mixed",
+		description: "Findings of all the severities.",
+		severity:    spec.SeverityError,
+		check: func(r rule, as *spec.ApiSpec) spec.Diagnostics {
+			var diagnostics spec.Diagnostics
+			for _, severity := range []spec.Severity{spec.SeverityError, spec.SeverityWarning, spec.SeverityInfo} {
+				d := r.diagnostic("types.Message", "finding of %s severity", severity)
+				d.Severity = severity
+				diagnostics = append(diagnostics, d)
+			}
+
+			return diagnostics
+		},
+	}
+}
+
+func emptySpec(t *testing.T) *spec.ApiSpec {
+	t.Helper()
+
+	return spectest.New(t, func(as *spec.ApiSpec) error {
+		return nil
+	})
+}
+
+func TestSeverityOverride(t *testing.T) {
+	registry, err := NewRegistry(mixedRule())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linter, err := NewLinter(registry, Config{Severity: map[string]spec.Severity{"mixed": spec.SeverityInfo}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diagnostics := linter.Lint(emptySpec(t))
+	if len(diagnostics) != 3 {
+		t.Fatalf("expected 3 findings, got %v", diagnostics)
+	}
+	for _, d := range diagnostics {
+		if d.Severity != spec.SeverityInfo {
+			t.Errorf("severity isn't overridden: %s %s", d.Severity, d.Message)
+		}
+	}
+}
+
+func TestNewLinterRejectsUnknownRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"disabled", Config{Disabled: []string{"unknown"}}},
+		{"severity", Config{Severity: map[string]spec.Severity{"unknown": spec.SeverityInfo}}},
+		{"unknown severity", Config{Severity: map[string]spec.Severity{"self-check": "fatal"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewLinter(DefaultRegistry(), tt.config); err == nil {
+				t.Error("expected the error")
+			}
+		})
+	}
+}
+
+func TestSARIFSelfCheckFindings(t *testing.T) {
+	registry := DefaultRegistry()
+	selfCheck, _ := registry.GetRule(selfCheckRule)
+	diagnostics := spec.Diagnostics{{Code: spec.CodeMissingVersion, Severity: spec.SeverityError, Path: "version", Message: "version not set"}}
+
+	content, err := SARIF(diagnostics, []Rule{selfCheck}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var log struct {
+		Runs []struct {
+			Results []struct {
+				RuleId     string            `json:"ruleId"`
+				Properties map[string]string `json:"properties"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(content, &log); err != nil {
+		t.Fatal(err)
+	}
+
+	result := log.Runs[0].Results[0]
+	if result.RuleId != selfCheckRule || result.Properties["code"] != string(spec.CodeMissingVersion) {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
@@ -0,0 +1,290 @@
+package spec_lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+var (
+	returnSentenceRegexp = regexp.MustCompile(`(?i)[^.]*\b(?:on success|returns|is returned|are returned)\b[^.]*`)
+	typeNameRegexp       = regexp.MustCompile(`\b[A-Z][A-Za-z0-9]+\b`)
+)
+
+// selfCheckRule is the name of the rule which reports findings of the spec self check with their own codes.
+const selfCheckRule = "self-check"
+
+// rule is the built-in rule which reports findings with its own name as the code.
+type rule struct {
+	name        string
+	description string
+	severity    spec.Severity
+	check       func(r rule, as *spec.ApiSpec) spec.Diagnostics
+}
+
+func (r rule) Name() string {
+	return r.name
+}
+
+func (r rule) Description() string {
+	return r.description
+}
+
+func (r rule) Severity() spec.Severity {
+	return r.severity
+}
+
+func (r rule) Check(as *spec.ApiSpec) spec.Diagnostics {
+	return r.check(r, as)
+}
+
+func (r rule) diagnostic(path, format string, args ...interface{}) spec.Diagnostic {
+	return spec.Diagnostic{
+		Code:     spec.DiagnosticCode(r.name),
+		Severity: r.severity,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+func builtinRules() []Rule {
+	return []Rule{
+		rule{
+			name:        selfCheckRule,
+			description: "Findings of the spec self check. They keep their own codes.",
+			severity:    spec.SeverityError,
+			check:       checkSelf,
+		},
+		rule{
+			name:        "unreferenced-type",
+			description: "Types which aren't used by any property, argument, return type or type hierarchy.",
+			severity:    spec.SeverityWarning,
+			check:       checkUnreferencedTypes,
+		},
+		rule{
+			name:        "unlisted-return-type",
+			description: "Methods which mention a type in the sentence about the result, but don't list it in return types.",
+			severity:    spec.SeverityWarning,
+			check:       checkUnlistedReturnTypes,
+		},
+		rule{
+			name:        "optional-mismatch",
+			description: "Optional properties which description doesn't start with 'Optional.' and vice versa.",
+			severity:    spec.SeverityWarning,
+			check:       checkOptionalMismatch,
+		},
+		rule{
+			name:        "duplicate-name",
+			description: "Properties of a type or arguments of a method with the same name.",
+			severity:    spec.SeverityError,
+			check:       checkDuplicateNames,
+		},
+		rule{
+			name:        "untyped-array",
+			description: "Arrays without the data type of elements.",
+			severity:    spec.SeverityWarning,
+			check:       checkUntypedArrays,
+		},
+		rule{
+			name:        "inheritance-cycle",
+			description: "Types which are their own ancestors through parent/child relations.",
+			severity:    spec.SeverityError,
+			check:       checkInheritanceCycles,
+		},
+	}
+}
+
+func checkSelf(r rule, as *spec.ApiSpec) spec.Diagnostics {
+	return as.Diagnose()
+}
+
+func checkUnreferencedTypes(r rule, as *spec.ApiSpec) spec.Diagnostics {
+	referenced := make(map[string]bool)
+	mark := func(dataTypes []spec.DataTypeDefinition) {
+		for _, dt := range dataTypes {
+			for _, t := range spec.ReferencedTypes(dt) {
+				referenced[t.GetName()] = true
+			}
+		}
+	}
+
+	for _, t := range as.GetTypes() {
+		if len(t.GetParents()) > 0 {
+			referenced[t.GetName()] = true
+		}
+		for _, p := range t.GetProperties() {
+			mark(p.GetDataTypes())
+		}
+	}
+
+	for _, m := range as.GetMethods() {
+		mark(m.GetReturnTypes())
+		for _, a := range m.GetArguments() {
+			mark(a.GetDataTypes())
+		}
+	}
+
+	var diagnostics spec.Diagnostics
+	for _, t := range as.GetTypes() {
+		if !referenced[t.GetName()] {
+			diagnostics = append(diagnostics, r.diagnostic("types."+t.GetName(), "type %s isn't used anywhere in the spec", t.GetName()))
+		}
+	}
+
+	return diagnostics
+}
+
+func checkUnlistedReturnTypes(r rule, as *spec.ApiSpec) spec.Diagnostics {
+	var diagnostics spec.Diagnostics
+	for _, m := range as.GetMethods() {
+		returns := make(map[string]bool)
+		for _, dt := range m.GetReturnTypes() {
+			for _, t := range spec.ReferencedTypes(dt) {
+				returns[t.GetName()] = true
+			}
+		}
+
+		mentioned := make(map[string]bool)
+		for _, sentence := range returnSentenceRegexp.FindAllString(m.GetDescription(), -1) {
+			for _, name := range typeNameRegexp.FindAllString(sentence, -1) {
+				if _, exists := as.GetType(name); exists && !returns[name] {
+					mentioned[name] = true
+				}
+			}
+		}
+
+		for _, name := range sortedNames(mentioned) {
+			diagnostics = append(diagnostics, r.diagnostic("methods."+m.GetName(), "method %s mentions %s as a result, but doesn't return it", m.GetName(), name))
+		}
+	}
+
+	return diagnostics
+}
+
+func checkOptionalMismatch(r rule, as *spec.ApiSpec) spec.Diagnostics {
+	var diagnostics spec.Diagnostics
+	for _, t := range as.GetTypes() {
+		for _, p := range t.GetProperties() {
+			path := "types." + t.GetName() + ".properties." + p.GetName()
+			hasText := strings.HasPrefix(strings.TrimSpace(p.GetDescription()), "Optional.")
+			switch {
+			case p.IsOptional() && !hasText:
+				diagnostics = append(diagnostics, r.diagnostic(path, "optional property %s of type %s isn't described as 'Optional.'", p.GetName(), t.GetName()))
+			case !p.IsOptional() && hasText:
+				diagnostics = append(diagnostics, r.diagnostic(path, "required property %s of type %s is described as 'Optional.'", p.GetName(), t.GetName()))
+			}
+		}
+	}
+
+	return diagnostics
+}
+
+func checkDuplicateNames(r rule, as *spec.ApiSpec) spec.Diagnostics {
+	var diagnostics spec.Diagnostics
+	for _, t := range as.GetTypes() {
+		seen := make(map[string]bool)
+		for _, p := range t.GetProperties() {
+			if seen[p.GetName()] {
+				diagnostics = append(diagnostics, r.diagnostic("types."+t.GetName()+".properties."+p.GetName(), "type %s has several properties named %s", t.GetName(), p.GetName()))
+			}
+			seen[p.GetName()] = true
+		}
+	}
+
+	for _, m := range as.GetMethods() {
+		seen := make(map[string]bool)
+		for _, a := range m.GetArguments() {
+			if seen[a.GetName()] {
+				diagnostics = append(diagnostics, r.diagnostic("methods."+m.GetName()+".arguments."+a.GetName(), "method %s has several arguments named %s", m.GetName(), a.GetName()))
+			}
+			seen[a.GetName()] = true
+		}
+	}
+
+	return diagnostics
+}
+
+func checkUntypedArrays(r rule, as *spec.ApiSpec) spec.Diagnostics {
+	var diagnostics spec.Diagnostics
+	report := func(path string, dataTypes []spec.DataTypeDefinition) {
+		for _, dt := range dataTypes {
+			if hasUntypedArray(dt) {
+				diagnostics = append(diagnostics, r.diagnostic(path, "data type %s has an array without the data type of elements", dt.GetDefinition()))
+			}
+		}
+	}
+
+	for _, t := range as.GetTypes() {
+		for _, p := range t.GetProperties() {
+			report("types."+t.GetName()+".properties."+p.GetName(), p.GetDataTypes())
+		}
+	}
+
+	for _, m := range as.GetMethods() {
+		report("methods."+m.GetName(), m.GetReturnTypes())
+		for _, a := range m.GetArguments() {
+			report("methods."+m.GetName()+".arguments."+a.GetName(), a.GetDataTypes())
+		}
+	}
+
+	return diagnostics
+}
+
+func hasUntypedArray(dataType spec.DataTypeDefinition) bool {
+	switch dt := dataType.(type) {
+	case *spec.ArrayDataType:
+		return dt.GetElementDataType() == nil || hasUntypedArray(dt.GetElementDataType())
+	case *spec.UnionDataType:
+		for _, member := range dt.GetMemberDataTypes() {
+			if hasUntypedArray(member) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func checkInheritanceCycles(r rule, as *spec.ApiSpec) spec.Diagnostics {
+	var diagnostics spec.Diagnostics
+	for _, t := range as.GetTypes() {
+		if path := findCycle(t, t, map[string]bool{}); path != nil {
+			diagnostics = append(diagnostics, r.diagnostic("types."+t.GetName(), "type %s is its own ancestor: %s", t.GetName(), strings.Join(path, " → ")))
+		}
+	}
+
+	return diagnostics
+}
+
+// findCycle returns the chain of children from the current type back to the start type or nil if there is no such chain.
+func findCycle(start, current *spec.TgTypeSpec, visited map[string]bool) []string {
+	visited[current.GetName()] = true
+	for _, child := range current.GetChildren() {
+		if child.GetName() == start.GetName() {
+			return []string{current.GetName(), child.GetName()}
+		}
+
+		if visited[child.GetName()] {
+			continue
+		}
+
+		if path := findCycle(start, child, visited); path != nil {
+			return append([]string{current.GetName()}, path...)
+		}
+	}
+
+	return nil
+}
+
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
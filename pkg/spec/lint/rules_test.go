@@ -0,0 +1,211 @@
+package spec_lint
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+func TestBuiltinRules(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		fill spectest.Fill
+		want []string
+	}{
+		{
+			name: "unused type",
+			rule: "unreferenced-type",
+			fill: func(as *spec.ApiSpec) error {
+				spectest.AddType(as, "Chat")
+				spectest.AddType(as, "Orphan")
+				spectest.AddMethod(as, "getChat", "Chat")
+
+				return nil
+			},
+			want: []string{"types.Orphan"},
+		},
+		{
+			name: "types used by properties, arguments and hierarchies",
+			rule: "unreferenced-type",
+			fill: func(as *spec.ApiSpec) error {
+				spectest.AddType(as, "User")
+				spectest.AddType(as, "Chat", "photo ChatPhoto")
+				spectest.AddType(as, "ChatPhoto")
+				spectest.AddChildren(spectest.AddType(as, "BotCommandScope"), spectest.AddType(as, "BotCommandScopeDefault"))
+				spectest.AddMethod(as, "getMe", "User", "chat_id int64", "scope BotCommandScope")
+				spectest.AddMethod(as, "getChat", "array<Chat>")
+
+				return nil
+			},
+		},
+		{
+			name: "type mentioned in the result sentence",
+			rule: "unlisted-return-type",
+			fill: func(as *spec.ApiSpec) error {
+				spectest.AddType(as, "User")
+				m := spectest.AddMethod(as, "getMe", "true")
+				m.SetDescription("A simple method for testing your bot's authentication token. Requires no parameters. Returns basic information about the bot in form of a User object.")
+
+				return nil
+			},
+			want: []string{"methods.getMe"},
+		},
+		{
+			name: "listed return types",
+			rule: "unlisted-return-type",
+			fill: func(as *spec.ApiSpec) error {
+				spectest.AddType(as, "Update")
+				spectest.AddType(as, "Message")
+				spectest.AddType(as, "InputFile")
+				getUpdates := spectest.AddMethod(as, "getUpdates", "array<Update>")
+				getUpdates.SetDescription("Use this method to receive incoming updates using long polling (wiki). Returns an Array of Update objects.")
+				editMessageText := spectest.AddMethod(as, "editMessageText", "Message|true")
+				editMessageText.SetDescription("Use this method to edit text and game messages. On success, if the edited message is not an inline message, the edited Message is returned, otherwise True is returned.")
+				sendDocument := spectest.AddMethod(as, "sendDocument", "Message")
+				sendDocument.SetDescription("Use this method to send general files. Pass an InputFile to upload a new file. On success, the sent Message is returned.")
+
+				return nil
+			},
+		},
+		{
+			name: "optional flag and text disagree",
+			rule: "optional-mismatch",
+			fill: func(as *spec.ApiSpec) error {
+				chat := spectest.AddType(as, "Chat", "title string", "username string")
+				title, username := chat.GetProperties()[0], chat.GetProperties()[1]
+				title.SetOptional(true)
+				title.SetDescription("Title, for supergroups, channels and group chats")
+				username.SetDescription("Optional. Username, for private chats, supergroups and channels if available")
+
+				return nil
+			},
+			want: []string{"types.Chat.properties.title", "types.Chat.properties.username"},
+		},
+		{
+			name: "optional flag and text agree",
+			rule: "optional-mismatch",
+			fill: func(as *spec.ApiSpec) error {
+				chat := spectest.AddType(as, "Chat", "id int64", "title string")
+				id, title := chat.GetProperties()[0], chat.GetProperties()[1]
+				id.SetDescription("Unique identifier for this chat.")
+				title.SetOptional(true)
+				title.SetDescription("Optional. Title, for supergroups, channels and group chats")
+
+				return nil
+			},
+		},
+		{
+			name: "duplicate properties and arguments",
+			rule: "duplicate-name",
+			fill: func(as *spec.ApiSpec) error {
+				spectest.AddType(as, "Chat", "id int64", "id string")
+				spectest.AddMethod(as, "getChat", "Chat", "chat_id int64", "chat_id string")
+
+				return nil
+			},
+			want: []string{"methods.getChat.arguments.chat_id", "types.Chat.properties.id"},
+		},
+		{
+			name: "unique properties and arguments",
+			rule: "duplicate-name",
+			fill: func(as *spec.ApiSpec) error {
+				spectest.AddType(as, "Chat", "id int64", "title string")
+				spectest.AddType(as, "User", "id int64")
+				spectest.AddMethod(as, "getChat", "Chat", "chat_id int64")
+				spectest.AddMethod(as, "getChatMember", "User", "chat_id int64", "user_id int64")
+
+				return nil
+			},
+		},
+		{
+			name: "arrays without elements",
+			rule: "untyped-array",
+			fill: func(as *spec.ApiSpec) error {
+				spectest.AddType(as, "Chat", "photos array<array>", "usernames array")
+				spectest.AddMethod(as, "getChats", "array", "ids array|int64")
+
+				return nil
+			},
+			want: []string{"methods.getChats", "methods.getChats.arguments.ids", "types.Chat.properties.photos", "types.Chat.properties.usernames"},
+		},
+		{
+			name: "typed arrays",
+			rule: "untyped-array",
+			fill: func(as *spec.ApiSpec) error {
+				spectest.AddType(as, "PhotoSize")
+				spectest.AddType(as, "UserProfilePhotos", "photos array<array<PhotoSize>>")
+				spectest.AddMethod(as, "getUpdates", "array<string>", "allowed_updates array<string>|string")
+
+				return nil
+			},
+		},
+		{
+			name: "types which are their own ancestors",
+			rule: "inheritance-cycle",
+			fill: func(as *spec.ApiSpec) error {
+				a, b, c := spectest.AddType(as, "A"), spectest.AddType(as, "B"), spectest.AddType(as, "C")
+				spectest.AddChildren(a, b)
+				spectest.AddChildren(b, c)
+				spectest.AddChildren(c, a)
+				spectest.AddChildren(c, spectest.AddType(as, "D"))
+
+				return nil
+			},
+			want: []string{"types.A", "types.B", "types.C"},
+		},
+		{
+			name: "hierarchy with shared children",
+			rule: "inheritance-cycle",
+			fill: func(as *spec.ApiSpec) error {
+				message, inaccessible := spectest.AddType(as, "Message"), spectest.AddType(as, "InaccessibleMessage")
+				spectest.AddChildren(spectest.AddType(as, "MaybeInaccessibleMessage"), message, inaccessible)
+				spectest.AddChildren(spectest.AddType(as, "Origin"), message)
+
+				return nil
+			},
+		},
+	}
+
+	registry := DefaultRegistry()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, exists := registry.GetRule(tt.rule)
+			if !exists {
+				t.Fatalf("rule %s isn't registered", tt.rule)
+			}
+
+			var paths []string
+			for _, d := range rule.Check(spectest.New(t, tt.fill)) {
+				if d.Code != spec.DiagnosticCode(tt.rule) || d.Severity != rule.Severity() {
+					t.Errorf("unexpected finding %s", d)
+				}
+				paths = append(paths, d.Path)
+			}
+			sort.Strings(paths)
+
+			if !reflect.DeepEqual(paths, tt.want) {
+				t.Errorf("got findings at %v, want %v", paths, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindCycle(t *testing.T) {
+	var a *spec.TgTypeSpec
+	spectest.New(t, func(as *spec.ApiSpec) error {
+		a = spectest.AddType(as, "A")
+		b, c := spectest.AddType(as, "B"), spectest.AddType(as, "C")
+		spectest.AddChildren(a, b, c)
+		spectest.AddChildren(c, a)
+
+		return nil
+	})
+
+	if got := findCycle(a, a, map[string]bool{}); !reflect.DeepEqual(got, []string{"A", "C", "A"}) {
+		t.Errorf("got %v", got)
+	}
+}
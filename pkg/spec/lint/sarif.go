@@ -0,0 +1,81 @@
+package spec_lint
+
+import (
+	"encoding/json"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolURI      = "https://github.com/alserom/tg-bot-api-spec"
+)
+
+// SARIF renders diagnostics as the SARIF 2.1.0 log, which is understood by code scanning tools.
+// Elements of the spec have no source lines, so findings are located by the logical element path.
+// Findings of the self check keep their own codes, so they're reported as the self-check rule with the code in properties.
+func SARIF(diagnostics spec.Diagnostics, rules []Rule, toolVersion string) ([]byte, error) {
+	known := make(map[string]bool, len(rules))
+	sarifRules := make([]map[string]interface{}, len(rules))
+	for i, r := range rules {
+		known[r.Name()] = true
+		sarifRules[i] = map[string]interface{}{
+			"id":                   r.Name(),
+			"shortDescription":     map[string]string{"text": r.Description()},
+			"defaultConfiguration": map[string]string{"level": sarifLevel(r.Severity())},
+		}
+	}
+
+	results := make([]map[string]interface{}, len(diagnostics))
+	for i, d := range diagnostics {
+		result := map[string]interface{}{
+			"ruleId":  string(d.Code),
+			"level":   sarifLevel(d.Severity),
+			"message": map[string]string{"text": d.Message},
+			"locations": []map[string]interface{}{
+				{
+					"logicalLocations": []map[string]string{
+						{"fullyQualifiedName": d.Path},
+					},
+				},
+			},
+		}
+		if !known[string(d.Code)] {
+			result["ruleId"] = selfCheckRule
+			result["properties"] = map[string]string{"code": string(d.Code)}
+		}
+		results[i] = result
+	}
+
+	log := map[string]interface{}{
+		"version": sarifVersion,
+		"$schema": sarifSchema,
+		"runs": []map[string]interface{}{
+			{
+				"tool": map[string]interface{}{
+					"driver": map[string]interface{}{
+						"name":           "spec-lint",
+						"version":        toolVersion,
+						"informationUri": toolURI,
+						"rules":          sarifRules,
+					},
+				},
+				"results": results,
+			},
+		},
+	}
+
+	return json.MarshalIndent(log, "", "    ")
+}
+
+func sarifLevel(severity spec.Severity) string {
+	switch severity {
+	case spec.SeverityError:
+		return "error"
+	case spec.SeverityWarning:
+		return "warning"
+	}
+
+	return "note"
+}
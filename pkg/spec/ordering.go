@@ -0,0 +1,44 @@
+package spec
+
+import (
+	"sort"
+)
+
+// Ordering defines how the elements of the spec should be sorted.
+type Ordering int
+
+const (
+	// OrderAlphabetical sorts elements by their names.
+	OrderAlphabetical Ordering = iota
+	// OrderDocument keeps the order in which elements are described in the official doc.
+	// Elements with an unknown position are placed at the end in alphabetical order.
+	OrderDocument
+)
+
+type orderedElement interface {
+	GetName() string
+	GetOrder() int
+}
+
+func sortElements[T orderedElement](elements []T, ordering Ordering) []T {
+	sorted := make([]T, len(elements))
+	copy(sorted, elements)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if ordering == OrderDocument && a.GetOrder() != b.GetOrder() {
+			switch {
+			case a.GetOrder() == 0:
+				return false
+			case b.GetOrder() == 0:
+				return true
+			}
+
+			return a.GetOrder() < b.GetOrder()
+		}
+
+		return a.GetName() < b.GetName()
+	})
+
+	return sorted
+}
@@ -0,0 +1,70 @@
+package spec_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+// newOrderedSpec fills the spec where the document order differs from the alphabetical one.
+// `Animal`, `getChat` and `answerCallbackQuery` have no position, e.g. they're added by the supplement.
+func newOrderedSpec(t *testing.T) *spec.ApiSpec {
+	t.Helper()
+
+	return spectest.New(t, func(as *spec.ApiSpec) error {
+		for name, order := range map[string]int{"Update": 1, "User": 2, "Chat": 3, "Animal": 0} {
+			spectest.AddType(as, name, "id int64").SetOrder(order)
+		}
+		for name, order := range map[string]int{"getUpdates": 10, "getMe": 11, "sendMessage": 12, "getChat": 0, "answerCallbackQuery": 0} {
+			spectest.AddMethod(as, name, "true").SetOrder(order)
+		}
+
+		return nil
+	})
+}
+
+func TestGetOrderedTypes(t *testing.T) {
+	as := newOrderedSpec(t)
+
+	tests := []struct {
+		ordering spec.Ordering
+		want     string
+	}{
+		{spec.OrderAlphabetical, "Animal,Chat,Update,User"},
+		{spec.OrderDocument, "Update,User,Chat,Animal"},
+	}
+
+	for _, tt := range tests {
+		var names []string
+		for _, tgType := range as.GetOrderedTypes(tt.ordering) {
+			names = append(names, tgType.GetName())
+		}
+		if got := strings.Join(names, ","); got != tt.want {
+			t.Errorf("ordering %d: got %s, want %s", tt.ordering, got, tt.want)
+		}
+	}
+}
+
+func TestGetOrderedMethods(t *testing.T) {
+	as := newOrderedSpec(t)
+
+	tests := []struct {
+		ordering spec.Ordering
+		want     string
+	}{
+		{spec.OrderAlphabetical, "answerCallbackQuery,getChat,getMe,getUpdates,sendMessage"},
+		{spec.OrderDocument, "getUpdates,getMe,sendMessage,answerCallbackQuery,getChat"},
+	}
+
+	for _, tt := range tests {
+		var names []string
+		for _, m := range as.GetOrderedMethods(tt.ordering) {
+			names = append(names, m.GetName())
+		}
+		if got := strings.Join(names, ","); got != tt.want {
+			t.Errorf("ordering %d: got %s, want %s", tt.ordering, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package spec
+
+// Provenance describes where the element was scraped from: the anchor and the text of the heading of the type or the method,
+// the number of the node in the page content and, for properties and arguments, the table row with the raw HTML of its cells.
+type Provenance struct {
+	Anchor   string
+	Heading  string
+	Position int
+	Row      int
+	Cells    []string
+}
@@ -1,6 +1,7 @@
 package spec
 
 import (
+	"strings"
 	"sync"
 )
 
@@ -15,9 +16,11 @@ type ApiSpec struct {
 	types               map[string]*TgTypeSpec
 	methods             map[string]*TgMethodSpec
 	dataTypeDefinitions map[string]DataTypeDefinition
+	aliases             map[string]*DataTypeAlias
 	t_mu                *sync.RWMutex
 	m_mu                *sync.RWMutex
 	dtd_mu              *sync.RWMutex
+	al_mu               *sync.RWMutex
 }
 
 func (as *ApiSpec) SetVersion(version string) error {
@@ -78,6 +81,34 @@ func (as *ApiSpec) AddType(t *TgTypeSpec) error {
 	return nil
 }
 
+// RemoveType removes the type and its relations with parents and children. Data types which refer to the type become unresolved.
+// It returns false if the type doesn't exist.
+func (as *ApiSpec) RemoveType(name string) bool {
+	as.t_mu.Lock()
+	t, exists := as.types[name]
+	delete(as.types, name)
+	as.t_mu.Unlock()
+
+	if !exists {
+		return false
+	}
+
+	for _, parent := range t.GetParents() {
+		parent.RemoveChild(t)
+	}
+	for _, child := range t.GetChildren() {
+		child.RemoveParent(t)
+	}
+
+	as.dtd_mu.Lock()
+	if objDef, ok := as.dataTypeDefinitions[name].(*ObjectDataType); ok && objDef.GetRef() == t {
+		objDef.setRef(nil)
+	}
+	as.dtd_mu.Unlock()
+
+	return true
+}
+
 func (as ApiSpec) GetType(name string) (*TgTypeSpec, bool) {
 	as.t_mu.RLock()
 	item, exists := as.types[name]
@@ -90,6 +121,17 @@ func (as ApiSpec) GetTypes() map[string]*TgTypeSpec {
 	return as.types
 }
 
+func (as ApiSpec) GetOrderedTypes(ordering Ordering) []*TgTypeSpec {
+	as.t_mu.RLock()
+	types := make([]*TgTypeSpec, 0, len(as.types))
+	for _, t := range as.types {
+		types = append(types, t)
+	}
+	as.t_mu.RUnlock()
+
+	return sortElements(types, ordering)
+}
+
 func (as *ApiSpec) AddMethod(m *TgMethodSpec) error {
 	if m == nil {
 		return skippedAddingNilPoiner()
@@ -102,6 +144,16 @@ func (as *ApiSpec) AddMethod(m *TgMethodSpec) error {
 	return nil
 }
 
+// RemoveMethod returns false if the method doesn't exist.
+func (as *ApiSpec) RemoveMethod(name string) bool {
+	as.m_mu.Lock()
+	_, exists := as.methods[name]
+	delete(as.methods, name)
+	as.m_mu.Unlock()
+
+	return exists
+}
+
 func (as ApiSpec) GetMethod(name string) (*TgMethodSpec, bool) {
 	as.m_mu.RLock()
 	item, exists := as.methods[name]
@@ -114,7 +166,20 @@ func (as ApiSpec) GetMethods() map[string]*TgMethodSpec {
 	return as.methods
 }
 
+func (as ApiSpec) GetOrderedMethods(ordering Ordering) []*TgMethodSpec {
+	as.m_mu.RLock()
+	methods := make([]*TgMethodSpec, 0, len(as.methods))
+	for _, m := range as.methods {
+		methods = append(methods, m)
+	}
+	as.m_mu.RUnlock()
+
+	return sortElements(methods, ordering)
+}
+
 func (as *ApiSpec) DeclareDataType(definition string) DataTypeDefinition {
+	definition = canonicalDefinition(definition)
+
 	as.dtd_mu.Lock()
 	dataType, exists := as.dataTypeDefinitions[definition]
 	as.dtd_mu.Unlock()
@@ -124,17 +189,54 @@ func (as *ApiSpec) DeclareDataType(definition string) DataTypeDefinition {
 
 	newDataType := newDataTypeDefinition(definition, as)
 	as.dtd_mu.Lock()
-	as.dataTypeDefinitions[newDataType.GetDefinition()] = newDataType
-	as.dtd_mu.Unlock()
+	defer as.dtd_mu.Unlock()
+	if dataType, exists := as.dataTypeDefinitions[definition]; exists {
+		return dataType
+	}
+	as.dataTypeDefinitions[definition] = newDataType
 
 	return newDataType
 }
 
+// PruneDataTypes removes declared data types which aren't used by any element or alias, e.g. the ones left after removing a type.
+// Data types of existing types are kept.
+func (as *ApiSpec) PruneDataTypes() {
+	used := usedDataTypes(*as)
+
+	as.t_mu.RLock()
+	defer as.t_mu.RUnlock()
+	as.dtd_mu.Lock()
+	defer as.dtd_mu.Unlock()
+
+	for definition := range as.dataTypeDefinitions {
+		if _, exists := used[definition]; exists {
+			continue
+		}
+		if _, exists := as.types[definition]; exists {
+			continue
+		}
+		delete(as.dataTypeDefinitions, definition)
+	}
+}
+
+// DeclareUnionDataType declares the union of the provided definitions.
+// If only one unique definition is provided, the data type of this definition is returned.
+func (as *ApiSpec) DeclareUnionDataType(definitions ...string) DataTypeDefinition {
+	return as.DeclareDataType(strings.Join(definitions, "|"))
+}
+
 func (as ApiSpec) GetDataTypeDefinitions() map[string]DataTypeDefinition {
 	return as.dataTypeDefinitions
 }
 
+// SelfCheck returns the CompositeError of all the error diagnostics or nil if the spec is valid.
+// Warnings and infos don't make the spec invalid, use Diagnose to get them.
 func (as ApiSpec) SelfCheck() error {
+	return check(as).Err()
+}
+
+// Diagnose returns all the findings of the spec check sorted by element path.
+func (as ApiSpec) Diagnose() Diagnostics {
 	return check(as)
 }
 
@@ -143,9 +245,11 @@ func NewApiSpec(ds DataSource) (*ApiSpec, error) {
 		types:               make(map[string]*TgTypeSpec),
 		methods:             make(map[string]*TgMethodSpec),
 		dataTypeDefinitions: make(map[string]DataTypeDefinition),
+		aliases:             make(map[string]*DataTypeAlias),
 		t_mu:                &sync.RWMutex{},
 		m_mu:                &sync.RWMutex{},
 		dtd_mu:              &sync.RWMutex{},
+		al_mu:               &sync.RWMutex{},
 	}
 
 	if err := ds.FillApiSpec(as); err != nil {
@@ -0,0 +1,104 @@
+// Package spectest builds small specs for tests of the packages which work with the spec.
+package spectest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+)
+
+// Fill is the data source which fills the spec by the function.
+type Fill func(as *spec.ApiSpec) error
+
+func (f Fill) FillApiSpec(as *spec.ApiSpec) error {
+	return f(as)
+}
+
+// New returns the spec filled by the function. The test fails if the spec can't be created.
+func New(t testing.TB, fill Fill) *spec.ApiSpec {
+	t.Helper()
+
+	as, err := spec.NewApiSpec(fill)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return as
+}
+
+// Link returns the link of the element in the official doc.
+func Link(name string) string {
+	return "https://core.telegram.org/bots/api#" + strings.ToLower(name)
+}
+
+// AddType adds the type of the `available-types` category.
+// Properties are pairs of the name and the data type definition like `chat Chat`.
+func AddType(as *spec.ApiSpec, name string, properties ...string) *spec.TgTypeSpec {
+	t, err := spec.NewTgTypeSpec("available-types", name, Link(name))
+	if err != nil {
+		panic(err)
+	}
+
+	for _, property := range properties {
+		propertyName, definition := split(property)
+		p, err := spec.NewTgTypeSpecProperty(propertyName)
+		if err != nil {
+			panic(err)
+		}
+		p.AddDataType(as.DeclareDataType(definition))
+		t.AddProperty(p)
+	}
+	as.AddType(t)
+
+	return t
+}
+
+// AddMethod adds the method of the `available-methods` category which returns the data type, unless it's empty.
+// Arguments are required and described like properties of AddType.
+func AddMethod(as *spec.ApiSpec, name, returns string, arguments ...string) *spec.TgMethodSpec {
+	m, err := spec.NewTgMethodSpec("available-methods", name, Link(name))
+	if err != nil {
+		panic(err)
+	}
+
+	for _, argument := range arguments {
+		argumentName, definition := split(argument)
+		a, err := spec.NewTgMethodSpecArgument(argumentName)
+		if err != nil {
+			panic(err)
+		}
+		a.AddDataType(as.DeclareDataType(definition))
+		m.AddArgument(a)
+	}
+	if returns != "" {
+		m.AddReturnType(as.DeclareDataType(returns))
+	}
+	as.AddMethod(m)
+
+	return m
+}
+
+func split(element string) (string, string) {
+	name, definition, found := strings.Cut(element, " ")
+	if !found {
+		panic("expecting the name and the data type definition, got: " + element)
+	}
+
+	return name, definition
+}
+
+// AddChildren makes the types children of the parent, relations are added to both sides.
+func AddChildren(parent *spec.TgTypeSpec, children ...*spec.TgTypeSpec) {
+	for _, child := range children {
+		parent.AddChild(child)
+		child.AddParent(parent)
+	}
+}
+
+// SetRelease sets the version with the release date and the link required by the spec self check.
+func SetRelease(as *spec.ApiSpec, version string) {
+	as.SetVersion(version)
+	as.SetReleaseDate("December 29, 2023")
+	as.SetLink("https://core.telegram.org/bots/api-changelog#december-29-2023")
+}
@@ -0,0 +1,195 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Selection describes elements of the spec to keep in the subset. Categories select both types and methods.
+type Selection struct {
+	Methods    []string
+	Categories []string
+	Types      []string
+}
+
+func (s Selection) IsEmpty() bool {
+	return len(s.Methods) == 0 && len(s.Categories) == 0 && len(s.Types) == 0
+}
+
+// Subset returns the new spec which contains the selected elements and all the types they refer to, directly or transitively.
+// Parent/child relations with types outside the subset are dropped, aliases are kept only for unions which are still used.
+func (as ApiSpec) Subset(selection Selection) (*ApiSpec, error) {
+	methods, types, err := as.selectElements(selection)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range methods {
+		for _, dt := range m.returns {
+			types = appendReferencedTypes(types, dt)
+		}
+		for _, cr := range m.conditionalReturns {
+			types = appendReferencedTypes(types, cr.dataType)
+		}
+		for _, a := range m.arguments {
+			for _, dt := range a.dataTypes {
+				types = appendReferencedTypes(types, dt)
+			}
+		}
+	}
+
+	// types grows while iterating, so the loop visits the whole closure.
+	for i := 0; i < len(types); i++ {
+		for _, p := range types[i].properties {
+			for _, dt := range p.dataTypes {
+				types = appendReferencedTypes(types, dt)
+			}
+		}
+		for _, child := range types[i].children {
+			types = appendTypeOnce(types, child)
+		}
+	}
+
+	subset, err := NewApiSpec(&subsetSource{from: as, types: types, methods: methods})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := subset.SelfCheck(); err != nil {
+		return nil, errors.New("subset is inconsistent: " + err.Error())
+	}
+
+	return subset, nil
+}
+
+func (as ApiSpec) selectElements(selection Selection) ([]*TgMethodSpec, []*TgTypeSpec, error) {
+	var methods []*TgMethodSpec
+	var types []*TgTypeSpec
+	var unknown []string
+
+	for _, name := range selection.Methods {
+		m, exists := as.methods[name]
+		if !exists {
+			unknown = append(unknown, "method "+name)
+			continue
+		}
+		methods = appendMethodOnce(methods, m)
+	}
+
+	for _, name := range selection.Types {
+		t, exists := as.types[name]
+		if !exists {
+			unknown = append(unknown, "type "+name)
+			continue
+		}
+		types = appendTypeOnce(types, t)
+	}
+
+	for _, category := range selection.Categories {
+		found := false
+		for _, m := range as.GetOrderedMethods(OrderAlphabetical) {
+			if m.category == category {
+				methods = appendMethodOnce(methods, m)
+				found = true
+			}
+		}
+		for _, t := range as.GetOrderedTypes(OrderAlphabetical) {
+			if t.category == category {
+				types = appendTypeOnce(types, t)
+				found = true
+			}
+		}
+		if !found {
+			unknown = append(unknown, "category "+category)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return nil, nil, errors.New(fmt.Sprintf("unknown elements in the selection: %s", strings.Join(unknown, ", ")))
+	}
+
+	return methods, types, nil
+}
+
+// subsetSource fills the spec with copies of the selected elements, so the subset doesn't share mutable state with the origin.
+type subsetSource struct {
+	from    ApiSpec
+	types   []*TgTypeSpec
+	methods []*TgMethodSpec
+}
+
+func (ss subsetSource) FillApiSpec(as *ApiSpec) error {
+	as.version = ss.from.version
+	as.releaseDate = ss.from.releaseDate
+	as.link = ss.from.link
+
+	copies := make(map[string]*TgTypeSpec, len(ss.types))
+	for _, t := range ss.types {
+		c := as.CopyType(t)
+		copies[c.name] = c
+		if err := as.AddType(c); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range ss.types {
+		for _, child := range t.children {
+			if c, exists := copies[child.name]; exists {
+				copies[t.name].AddChild(c)
+			}
+		}
+		for _, parent := range t.parents {
+			if c, exists := copies[parent.name]; exists {
+				copies[t.name].AddParent(c)
+			}
+		}
+	}
+
+	for _, m := range ss.methods {
+		c, err := as.CopyMethod(m)
+		if err != nil {
+			return err
+		}
+		if err := as.AddMethod(c); err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, 0, len(ss.from.aliases))
+	for name := range ss.from.aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		definition := ss.from.aliases[name].dataType.GetDefinition()
+		if _, used := as.dataTypeDefinitions[definition]; !used {
+			continue
+		}
+		if _, err := as.DeclareAlias(name, definition); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func appendReferencedTypes(types []*TgTypeSpec, dataType DataTypeDefinition) []*TgTypeSpec {
+	for _, t := range ReferencedTypes(dataType) {
+		types = appendTypeOnce(types, t)
+	}
+
+	return types
+}
+
+func appendMethodOnce(methods []*TgMethodSpec, m *TgMethodSpec) []*TgMethodSpec {
+	for _, existing := range methods {
+		if existing.name == m.name {
+			return methods
+		}
+	}
+
+	return append(methods, m)
+}
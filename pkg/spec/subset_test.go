@@ -0,0 +1,226 @@
+package spec_test
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+// newOrigin fills the spec where `sendMessage` returns `Message`, which refers to `Chat`, `PhotoSize` and
+// `MaybeInaccessibleMessage`, the parent of `Message` and `InaccessibleMessage`. `sendSticker` of the `stickers`
+// category accepts `FileInput`. `getMe` returns `User`.
+func newOrigin(t *testing.T) *spec.ApiSpec {
+	t.Helper()
+
+	return spectest.New(t, func(as *spec.ApiSpec) error {
+		spectest.SetRelease(as, "7.0")
+
+		spectest.AddType(as, "User", "id int64")
+		spectest.AddType(as, "Chat", "id int64")
+		spectest.AddType(as, "PhotoSize", "file_id string")
+		spectest.AddType(as, "InputFile")
+		message := spectest.AddType(as, "Message", "chat Chat", "photo array<PhotoSize>", "pinned_message MaybeInaccessibleMessage")
+		text, _ := spec.NewTgTypeSpecProperty("text")
+		text.AddDataType(as.DeclareDataType("string"))
+		text.SetEnum([]string{"a", "b"})
+		text.SetConstraints(&spec.ValueConstraints{MaxLength: intPtr(4096)})
+		message.AddProperty(text)
+		inaccessible := spectest.AddType(as, "InaccessibleMessage", "chat Chat")
+		spectest.AddChildren(spectest.AddType(as, "MaybeInaccessibleMessage"), message, inaccessible)
+
+		spectest.AddMethod(as, "sendMessage", "Message", "chat_id int64|string", "text string")
+		spectest.AddMethod(as, "getMe", "User")
+
+		sticker, _ := spec.NewTgTypeSpec("stickers", "Sticker", spectest.Link("Sticker"))
+		as.AddType(sticker)
+		sendSticker, _ := spec.NewTgMethodSpec("stickers", "sendSticker", spectest.Link("sendSticker"))
+		file, _ := spec.NewTgMethodSpecArgument("sticker")
+		file.AddDataType(as.DeclareDataType("InputFile|string"))
+		sendSticker.AddArgument(file)
+		sendSticker.AddReturnType(as.DeclareDataType("Message"))
+		as.AddMethod(sendSticker)
+
+		if _, err := as.DeclareAlias("ChatId", "int64", "string"); err != nil {
+			return err
+		}
+		_, err := as.DeclareAlias("FileInput", "InputFile", "string")
+
+		return err
+	})
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func typeNames(as *spec.ApiSpec) []string {
+	var names []string
+	for _, t := range as.GetOrderedTypes(spec.OrderAlphabetical) {
+		names = append(names, t.GetName())
+	}
+
+	return names
+}
+
+func methodNames(as *spec.ApiSpec) []string {
+	var names []string
+	for _, m := range as.GetOrderedMethods(spec.OrderAlphabetical) {
+		names = append(names, m.GetName())
+	}
+
+	return names
+}
+
+func aliasNames(as *spec.ApiSpec) []string {
+	var names []string
+	for name := range as.GetAliases() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func TestSubset(t *testing.T) {
+	tests := []struct {
+		name      string
+		selection spec.Selection
+		types     []string
+		methods   []string
+		aliases   []string
+	}{
+		{
+			name:      "method",
+			selection: spec.Selection{Methods: []string{"sendMessage"}},
+			types:     []string{"Chat", "InaccessibleMessage", "MaybeInaccessibleMessage", "Message", "PhotoSize"},
+			methods:   []string{"sendMessage"},
+			aliases:   []string{"ChatId"},
+		},
+		{
+			name:      "method without references",
+			selection: spec.Selection{Methods: []string{"getMe"}},
+			types:     []string{"User"},
+			methods:   []string{"getMe"},
+		},
+		{
+			name:      "category",
+			selection: spec.Selection{Categories: []string{"stickers"}},
+			types:     []string{"Chat", "InaccessibleMessage", "InputFile", "MaybeInaccessibleMessage", "Message", "PhotoSize", "Sticker"},
+			methods:   []string{"sendSticker"},
+			aliases:   []string{"FileInput"},
+		},
+		{
+			name:      "type",
+			selection: spec.Selection{Types: []string{"Chat"}},
+			types:     []string{"Chat"},
+		},
+	}
+
+	origin := newOrigin(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subset, err := origin.Subset(tt.selection)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := subset.SelfCheck(); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := typeNames(subset); !reflect.DeepEqual(got, tt.types) {
+				t.Errorf("got types %v, want %v", got, tt.types)
+			}
+			if got := methodNames(subset); !reflect.DeepEqual(got, tt.methods) {
+				t.Errorf("got methods %v, want %v", got, tt.methods)
+			}
+			if got := aliasNames(subset); !reflect.DeepEqual(got, tt.aliases) {
+				t.Errorf("got aliases %v, want %v", got, tt.aliases)
+			}
+			if subset.GetVersion() != origin.GetVersion() {
+				t.Errorf("got version %s, want %s", subset.GetVersion(), origin.GetVersion())
+			}
+		})
+	}
+}
+
+func TestSubsetRejectsUnknownElements(t *testing.T) {
+	_, err := newOrigin(t).Subset(spec.Selection{
+		Methods:    []string{"sendMessage", "sendPhoto"},
+		Types:      []string{"Poll"},
+		Categories: []string{"games"},
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "method sendPhoto, type Poll, category games") {
+		t.Errorf("expected the error about unknown elements, got %v", err)
+	}
+}
+
+func TestSubsetDropsRelationsOutside(t *testing.T) {
+	subset, err := newOrigin(t).Subset(spec.Selection{Types: []string{"Message"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message, _ := subset.GetType("Message")
+	if len(message.GetParents()) != 1 || message.GetParents()[0].GetName() != "MaybeInaccessibleMessage" {
+		t.Errorf("Message must keep the parent referred by its property, got %v", message.GetParents())
+	}
+
+	subset, err = newOrigin(t).Subset(spec.Selection{Types: []string{"InaccessibleMessage"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inaccessible, _ := subset.GetType("InaccessibleMessage")
+	if len(inaccessible.GetParents()) != 0 {
+		t.Errorf("the parent outside the subset isn't dropped: %v", inaccessible.GetParents())
+	}
+	if _, exists := subset.GetType("MaybeInaccessibleMessage"); exists {
+		t.Errorf("the parent isn't selected, but it's in the subset")
+	}
+}
+
+func TestSubsetDoesNotShareState(t *testing.T) {
+	origin := newOrigin(t)
+	subset, err := origin.Subset(spec.Selection{Methods: []string{"sendMessage"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message, _ := subset.GetType("Message")
+	message.SetDescription("changed")
+	text := message.GetProperties()[3]
+	text.GetEnum()[0] = "changed"
+	*text.GetConstraints().MaxLength = 1
+	message.AddProperty(text)
+	maybe, _ := subset.GetType("MaybeInaccessibleMessage")
+	chat, _ := subset.GetType("Chat")
+	maybe.AddChild(chat)
+	sendMessage, _ := subset.GetMethod("sendMessage")
+	extra, _ := spec.NewTgMethodSpecArgument("extra")
+	sendMessage.AddArgument(extra)
+
+	originMessage, _ := origin.GetType("Message")
+	originText := originMessage.GetProperties()[3]
+	originMaybe, _ := origin.GetType("MaybeInaccessibleMessage")
+	originSendMessage, _ := origin.GetMethod("sendMessage")
+
+	switch {
+	case originMessage.GetDescription() != "":
+		t.Error("description of the origin is changed")
+	case len(originMessage.GetProperties()) != 4:
+		t.Error("properties of the origin are changed")
+	case originText.GetEnum()[0] != "a":
+		t.Error("enum of the origin is changed")
+	case *originText.GetConstraints().MaxLength != 4096:
+		t.Error("constraints of the origin are changed")
+	case len(originMaybe.GetChildren()) != 2:
+		t.Error("children of the origin are changed")
+	case len(originSendMessage.GetArguments()) != 2:
+		t.Error("arguments of the origin are changed")
+	}
+}
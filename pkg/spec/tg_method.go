@@ -1,18 +1,27 @@
 package spec
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
 )
 
 type TgMethodSpec struct {
-	category    string
-	name        string
-	link        string
-	description string
-	arguments   []*TgMethodSpecArgument
-	returns     []DataTypeDefinition
-	a_mu        *sync.RWMutex
-	r_mu        *sync.RWMutex
+	category           string
+	name               string
+	link               string
+	description        string
+	arguments          []*TgMethodSpecArgument
+	argumentGroups     []*TgMethodSpecArgumentGroup
+	returns            []DataTypeDefinition
+	conditionalReturns []*TgMethodSpecConditionalReturn
+	order              int
+	history            *VersionHistory
+	provenance         *Provenance
+	a_mu               *sync.RWMutex
+	ag_mu              *sync.RWMutex
+	r_mu               *sync.RWMutex
 }
 
 func (tms TgMethodSpec) GetCategory() string {
@@ -47,10 +56,69 @@ func (tms *TgMethodSpec) AddArgument(argument *TgMethodSpecArgument) error {
 	return nil
 }
 
+// ReplaceArgument replaces the argument of the same name keeping its position. It returns false if there is no such argument.
+func (tms *TgMethodSpec) ReplaceArgument(argument *TgMethodSpecArgument) bool {
+	if argument == nil {
+		return false
+	}
+
+	tms.a_mu.Lock()
+	defer tms.a_mu.Unlock()
+
+	for i, a := range tms.arguments {
+		if a.name == argument.name {
+			tms.arguments[i] = argument
+			return true
+		}
+	}
+
+	return false
+}
+
+// RemoveArgument returns false if there is no argument with the name.
+func (tms *TgMethodSpec) RemoveArgument(name string) bool {
+	tms.a_mu.Lock()
+	defer tms.a_mu.Unlock()
+
+	for i, a := range tms.arguments {
+		if a.name == name {
+			tms.arguments = append(tms.arguments[:i:i], tms.arguments[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
 func (tms TgMethodSpec) GetArguments() []*TgMethodSpecArgument {
 	return tms.arguments
 }
 
+func (tms TgMethodSpec) GetOrderedArguments(ordering Ordering) []*TgMethodSpecArgument {
+	tms.a_mu.RLock()
+	arguments := make([]*TgMethodSpecArgument, len(tms.arguments))
+	copy(arguments, tms.arguments)
+	tms.a_mu.RUnlock()
+
+	return sortElements(arguments, ordering)
+}
+
+func (tms *TgMethodSpec) AddArgumentGroup(group *TgMethodSpecArgumentGroup) error {
+	if group == nil {
+		return skippedAddingNilPoiner()
+	}
+
+	tms.ag_mu.Lock()
+	tms.argumentGroups = append(tms.argumentGroups, group)
+	tms.ag_mu.Unlock()
+
+	return nil
+}
+
+func (tms TgMethodSpec) GetArgumentGroups() []*TgMethodSpecArgumentGroup {
+	return tms.argumentGroups
+}
+
 func (tms *TgMethodSpec) AddReturnType(returnType DataTypeDefinition) error {
 	if returnType == nil {
 		return skippedAddingNilPoiner()
@@ -63,15 +131,74 @@ func (tms *TgMethodSpec) AddReturnType(returnType DataTypeDefinition) error {
 	return nil
 }
 
+// ClearReturnTypes removes return types and conditional returns, because the latter must be among the former.
+func (tms *TgMethodSpec) ClearReturnTypes() {
+	tms.r_mu.Lock()
+	tms.returns = nil
+	tms.conditionalReturns = nil
+	tms.r_mu.Unlock()
+}
+
 func (tms TgMethodSpec) GetReturnTypes() []DataTypeDefinition {
 	return tms.returns
 }
 
+// AddConditionalReturn describes under which condition one of the return types is returned.
+// The data type of the conditional return must be added to the return types too.
+func (tms *TgMethodSpec) AddConditionalReturn(conditionalReturn *TgMethodSpecConditionalReturn) error {
+	if conditionalReturn == nil {
+		return skippedAddingNilPoiner()
+	}
+
+	tms.r_mu.Lock()
+	tms.conditionalReturns = append(tms.conditionalReturns, conditionalReturn)
+	tms.r_mu.Unlock()
+
+	return nil
+}
+
+func (tms TgMethodSpec) GetConditionalReturns() []*TgMethodSpecConditionalReturn {
+	return tms.conditionalReturns
+}
+
+func (tms *TgMethodSpec) SetOrder(order int) {
+	tms.order = order
+}
+
+// GetOrder returns the position of the method in the official doc. Zero means the position is unknown.
+func (tms TgMethodSpec) GetOrder() int {
+	return tms.order
+}
+
+func (tms *TgMethodSpec) SetHistory(history *VersionHistory) {
+	tms.history = history
+}
+
+// GetHistory returns nil if versions of the method are unknown.
+func (tms TgMethodSpec) GetHistory() *VersionHistory {
+	return tms.history
+}
+
+func (tms *TgMethodSpec) SetProvenance(provenance *Provenance) {
+	tms.provenance = provenance
+}
+
+// GetProvenance returns nil if the method isn't scraped.
+func (tms TgMethodSpec) GetProvenance() *Provenance {
+	return tms.provenance
+}
+
 type TgMethodSpecArgument struct {
 	name        string
 	description string
 	required    bool
 	dataTypes   []DataTypeDefinition
+	enum        []string
+	constraints *ValueConstraints
+	defaultVal  interface{}
+	order       int
+	history     *VersionHistory
+	provenance  *Provenance
 	dt_mu       *sync.RWMutex
 }
 
@@ -107,10 +234,88 @@ func (tmsa *TgMethodSpecArgument) AddDataType(typeDef DataTypeDefinition) error
 	return nil
 }
 
+// ClearDataTypes also removes the default value, because it's checked against data types.
+func (tmsa *TgMethodSpecArgument) ClearDataTypes() {
+	tmsa.dt_mu.Lock()
+	tmsa.dataTypes = nil
+	tmsa.defaultVal = nil
+	tmsa.dt_mu.Unlock()
+}
+
 func (tmsa TgMethodSpecArgument) GetDataTypes() []DataTypeDefinition {
 	return tmsa.dataTypes
 }
 
+// SetEnum sets the list of values which the argument can be.
+func (tmsa *TgMethodSpecArgument) SetEnum(values []string) {
+	tmsa.enum = values
+}
+
+func (tmsa TgMethodSpecArgument) GetEnum() []string {
+	return tmsa.enum
+}
+
+func (tmsa *TgMethodSpecArgument) SetConstraints(constraints *ValueConstraints) {
+	tmsa.constraints = constraints
+}
+
+func (tmsa TgMethodSpecArgument) GetConstraints() *ValueConstraints {
+	return tmsa.constraints
+}
+
+// SetDefault sets the value which is used by Telegram when the argument is not passed.
+// The value must match one of the scalar data types of the argument, so data types must be added before.
+// Passing nil removes the default value.
+func (tmsa *TgMethodSpecArgument) SetDefault(value interface{}) error {
+	if value == nil {
+		tmsa.defaultVal = nil
+		return nil
+	}
+
+	for _, dt := range FlattenDataTypes(tmsa.dataTypes) {
+		if scalar, ok := dt.(*ScalarDataType); ok {
+			if v, ok := scalar.coerceValue(value); ok {
+				tmsa.defaultVal = v
+				return nil
+			}
+		}
+	}
+
+	return errors.New(fmt.Sprintf("default value '%v' doesn't match data types of argument %s", value, tmsa.name))
+}
+
+// GetDefault returns nil if the default value is not set. Otherwise, it returns int64, float64, bool or string.
+func (tmsa TgMethodSpecArgument) GetDefault() interface{} {
+	return tmsa.defaultVal
+}
+
+func (tmsa *TgMethodSpecArgument) SetOrder(order int) {
+	tmsa.order = order
+}
+
+// GetOrder returns the position of the argument in the official doc. Zero means the position is unknown.
+func (tmsa TgMethodSpecArgument) GetOrder() int {
+	return tmsa.order
+}
+
+func (tmsa *TgMethodSpecArgument) SetHistory(history *VersionHistory) {
+	tmsa.history = history
+}
+
+// GetHistory returns nil if versions of the argument are unknown.
+func (tmsa TgMethodSpecArgument) GetHistory() *VersionHistory {
+	return tmsa.history
+}
+
+func (tmsa *TgMethodSpecArgument) SetProvenance(provenance *Provenance) {
+	tmsa.provenance = provenance
+}
+
+// GetProvenance returns nil if the argument isn't scraped.
+func (tmsa TgMethodSpecArgument) GetProvenance() *Provenance {
+	return tmsa.provenance
+}
+
 func NewTgMethodSpec(category, name, link string) (*TgMethodSpec, error) {
 	var errs []error
 	checks := [3]error{
@@ -133,6 +338,7 @@ func NewTgMethodSpec(category, name, link string) (*TgMethodSpec, error) {
 		name:     name,
 		link:     link,
 		a_mu:     &sync.RWMutex{},
+		ag_mu:    &sync.RWMutex{},
 		r_mu:     &sync.RWMutex{},
 	}, nil
 }
@@ -149,3 +355,113 @@ func NewTgMethodSpecArgument(name string) (*TgMethodSpecArgument, error) {
 		dt_mu:    &sync.RWMutex{},
 	}, nil
 }
+
+type ArgumentGroupKind string
+
+const (
+	// ExactlyOneOf means that exactly one of the arguments must be passed.
+	ExactlyOneOf ArgumentGroupKind = "exactlyOneOf"
+	// RequiredIfAbsent means that the arguments are required unless all the `dependsOn` arguments are passed.
+	RequiredIfAbsent ArgumentGroupKind = "requiredIfAbsent"
+	// MutuallyExclusive means that at most one of the arguments can be passed.
+	MutuallyExclusive ArgumentGroupKind = "mutuallyExclusive"
+)
+
+// TgMethodSpecArgumentGroup describes a rule which binds several arguments of the method together.
+type TgMethodSpecArgumentGroup struct {
+	kind      ArgumentGroupKind
+	arguments []string
+	dependsOn []string
+}
+
+func (tmsag TgMethodSpecArgumentGroup) GetKind() ArgumentGroupKind {
+	return tmsag.kind
+}
+
+func (tmsag TgMethodSpecArgumentGroup) GetArguments() []string {
+	return tmsag.arguments
+}
+
+// GetDependsOn returns the arguments which affect the group. It's used only by the RequiredIfAbsent groups.
+func (tmsag TgMethodSpecArgumentGroup) GetDependsOn() []string {
+	return tmsag.dependsOn
+}
+
+func (tmsag TgMethodSpecArgumentGroup) String() string {
+	s := string(tmsag.kind) + "(" + strings.Join(tmsag.arguments, ", ")
+	if len(tmsag.dependsOn) > 0 {
+		s += "; " + strings.Join(tmsag.dependsOn, ", ")
+	}
+
+	return s + ")"
+}
+
+func NewTgMethodSpecArgumentGroup(kind ArgumentGroupKind, arguments, dependsOn []string) (*TgMethodSpecArgumentGroup, error) {
+	switch kind {
+	case ExactlyOneOf, MutuallyExclusive:
+		if len(arguments) < 2 {
+			return nil, errors.New(fmt.Sprintf("%s group requires at least two arguments", kind))
+		}
+		if len(dependsOn) > 0 {
+			return nil, errors.New(fmt.Sprintf("%s group can't depend on other arguments", kind))
+		}
+	case RequiredIfAbsent:
+		if len(arguments) == 0 || len(dependsOn) == 0 {
+			return nil, errors.New(fmt.Sprintf("%s group requires arguments and arguments it depends on", kind))
+		}
+	default:
+		return nil, errors.New(fmt.Sprintf("unknown kind of arguments group: %s", kind))
+	}
+
+	return &TgMethodSpecArgumentGroup{
+		kind:      kind,
+		arguments: arguments,
+		dependsOn: dependsOn,
+	}, nil
+}
+
+// TgMethodSpecConditionalReturn describes a data type which is returned by the method only under some condition.
+type TgMethodSpecConditionalReturn struct {
+	dataType  DataTypeDefinition
+	condition string
+	arguments []string
+}
+
+func (tmscr TgMethodSpecConditionalReturn) GetDataType() DataTypeDefinition {
+	return tmscr.dataType
+}
+
+// GetCondition returns the condition as it's written in the official doc, or "otherwise" for the fallback data type.
+func (tmscr TgMethodSpecConditionalReturn) GetCondition() string {
+	return tmscr.condition
+}
+
+func (tmscr *TgMethodSpecConditionalReturn) SetArguments(arguments []string) {
+	tmscr.arguments = arguments
+}
+
+// GetArguments returns names of the method arguments which decide the condition.
+func (tmscr TgMethodSpecConditionalReturn) GetArguments() []string {
+	return tmscr.arguments
+}
+
+func (tmscr TgMethodSpecConditionalReturn) String() string {
+	if tmscr.condition == "otherwise" {
+		return tmscr.dataType.GetDefinition() + " otherwise"
+	}
+
+	return tmscr.dataType.GetDefinition() + " if " + tmscr.condition
+}
+
+func NewTgMethodSpecConditionalReturn(dataType DataTypeDefinition, condition string) (*TgMethodSpecConditionalReturn, error) {
+	if dataType == nil {
+		return nil, errors.New("data type is required")
+	}
+
+	err := validateNonEmptyStringArg("condition", condition)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TgMethodSpecConditionalReturn{dataType: dataType, condition: condition}, nil
+}
@@ -0,0 +1,48 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/alserom/tg-bot-api-spec/pkg/spec"
+	"github.com/alserom/tg-bot-api-spec/pkg/spec/spectest"
+)
+
+func TestSetDefault(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		value      interface{}
+		want       interface{}
+		err        bool
+	}{
+		{"integer", "int32", 100, int64(100), false},
+		{"integer decoded from JSON", "int64", float64(100), int64(100), false},
+		{"fractional integer", "int32", 0.5, nil, true},
+		{"string", "string", "HTML", "HTML", false},
+		{"string of integer", "int32", "100", nil, true},
+		{"boolean", "boolean", true, true, false},
+		{"false literal", "true", false, nil, true},
+		{"member of union", "int64|string", "@channel", "@channel", false},
+		{"object", "Message", "text", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var argument *spec.TgMethodSpecArgument
+			spectest.New(t, func(as *spec.ApiSpec) error {
+				argument, _ = spec.NewTgMethodSpecArgument("argument")
+				argument.AddDataType(as.DeclareDataType(tt.definition))
+
+				return nil
+			})
+
+			err := argument.SetDefault(tt.value)
+			if (err != nil) != tt.err {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if got := argument.GetDefault(); got != tt.want {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,9 +9,13 @@ type TgTypeSpec struct {
 	name        string
 	link        string
 	description string
-	parent      *TgTypeSpec
+	parents     []*TgTypeSpec
 	children    []*TgTypeSpec
 	properties  []*TgTypeSpecProperty
+	order       int
+	history     *VersionHistory
+	provenance  *Provenance
+	pa_mu       *sync.RWMutex
 	c_mu        *sync.RWMutex
 	p_mu        *sync.RWMutex
 }
@@ -36,26 +40,51 @@ func (tts TgTypeSpec) GetDescription() string {
 	return tts.description
 }
 
-func (tts *TgTypeSpec) SetParent(parent *TgTypeSpec) {
-	tts.parent = parent
+// AddParent adds the abstract type which the type can be a subtype of. A type can be listed under several abstract types.
+// Adding the same parent twice has no effect.
+func (tts *TgTypeSpec) AddParent(parent *TgTypeSpec) error {
+	if parent == nil {
+		return skippedAddingNilPoiner()
+	}
+
+	tts.pa_mu.Lock()
+	tts.parents = appendTypeOnce(tts.parents, parent)
+	tts.pa_mu.Unlock()
+
+	return nil
 }
 
-func (tts TgTypeSpec) GetParent() *TgTypeSpec {
-	return tts.parent
+// RemoveParent removes only this end of the relation, the parent keeps the type as a child.
+func (tts *TgTypeSpec) RemoveParent(parent *TgTypeSpec) {
+	tts.pa_mu.Lock()
+	tts.parents = removeType(tts.parents, parent)
+	tts.pa_mu.Unlock()
 }
 
+func (tts TgTypeSpec) GetParents() []*TgTypeSpec {
+	return tts.parents
+}
+
+// AddChild adds the subtype of the type. Adding the same child twice has no effect.
 func (tts *TgTypeSpec) AddChild(child *TgTypeSpec) error {
 	if child == nil {
 		return skippedAddingNilPoiner()
 	}
 
 	tts.c_mu.Lock()
-	tts.children = append(tts.children, child)
+	tts.children = appendTypeOnce(tts.children, child)
 	tts.c_mu.Unlock()
 
 	return nil
 }
 
+// RemoveChild removes only this end of the relation, the child keeps the type as a parent.
+func (tts *TgTypeSpec) RemoveChild(child *TgTypeSpec) {
+	tts.c_mu.Lock()
+	tts.children = removeType(tts.children, child)
+	tts.c_mu.Unlock()
+}
+
 func (tts TgTypeSpec) GetChildren() []*TgTypeSpec {
 	return tts.children
 }
@@ -72,16 +101,91 @@ func (tts *TgTypeSpec) AddProperty(property *TgTypeSpecProperty) error {
 	return nil
 }
 
+// ReplaceProperty replaces the property of the same name keeping its position. It returns false if there is no such property.
+func (tts *TgTypeSpec) ReplaceProperty(property *TgTypeSpecProperty) bool {
+	if property == nil {
+		return false
+	}
+
+	tts.p_mu.Lock()
+	defer tts.p_mu.Unlock()
+
+	for i, p := range tts.properties {
+		if p.name == property.name {
+			tts.properties[i] = property
+			return true
+		}
+	}
+
+	return false
+}
+
+// RemoveProperty returns false if there is no property with the name.
+func (tts *TgTypeSpec) RemoveProperty(name string) bool {
+	tts.p_mu.Lock()
+	defer tts.p_mu.Unlock()
+
+	for i, p := range tts.properties {
+		if p.name == name {
+			tts.properties = append(tts.properties[:i:i], tts.properties[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
 func (tts TgTypeSpec) GetProperties() []*TgTypeSpecProperty {
 	return tts.properties
 }
 
+func (tts TgTypeSpec) GetOrderedProperties(ordering Ordering) []*TgTypeSpecProperty {
+	tts.p_mu.RLock()
+	properties := make([]*TgTypeSpecProperty, len(tts.properties))
+	copy(properties, tts.properties)
+	tts.p_mu.RUnlock()
+
+	return sortElements(properties, ordering)
+}
+
+func (tts *TgTypeSpec) SetOrder(order int) {
+	tts.order = order
+}
+
+// GetOrder returns the position of the type in the official doc. Zero means the position is unknown.
+func (tts TgTypeSpec) GetOrder() int {
+	return tts.order
+}
+
+func (tts *TgTypeSpec) SetHistory(history *VersionHistory) {
+	tts.history = history
+}
+
+// GetHistory returns nil if versions of the type are unknown.
+func (tts TgTypeSpec) GetHistory() *VersionHistory {
+	return tts.history
+}
+
+func (tts *TgTypeSpec) SetProvenance(provenance *Provenance) {
+	tts.provenance = provenance
+}
+
+// GetProvenance returns nil if the type isn't scraped.
+func (tts TgTypeSpec) GetProvenance() *Provenance {
+	return tts.provenance
+}
+
 type TgTypeSpecProperty struct {
 	name            string
 	description     string
 	dataTypes       []DataTypeDefinition
 	optional        bool
 	predefinedValue *TgTypeSpecPropertyValue
+	enum            []string
+	constraints     *ValueConstraints
+	order           int
+	history         *VersionHistory
+	provenance      *Provenance
 	dt_mu           *sync.RWMutex
 }
 
@@ -109,6 +213,12 @@ func (ttsp *TgTypeSpecProperty) AddDataType(typeDefinition DataTypeDefinition) e
 	return nil
 }
 
+func (ttsp *TgTypeSpecProperty) ClearDataTypes() {
+	ttsp.dt_mu.Lock()
+	ttsp.dataTypes = nil
+	ttsp.dt_mu.Unlock()
+}
+
 func (ttsp TgTypeSpecProperty) GetDataTypes() []DataTypeDefinition {
 	return ttsp.dataTypes
 }
@@ -129,6 +239,50 @@ func (ttsp TgTypeSpecProperty) GetPredefinedValue() *TgTypeSpecPropertyValue {
 	return ttsp.predefinedValue
 }
 
+// SetEnum sets the list of values which the property can be.
+func (ttsp *TgTypeSpecProperty) SetEnum(values []string) {
+	ttsp.enum = values
+}
+
+func (ttsp TgTypeSpecProperty) GetEnum() []string {
+	return ttsp.enum
+}
+
+func (ttsp *TgTypeSpecProperty) SetConstraints(constraints *ValueConstraints) {
+	ttsp.constraints = constraints
+}
+
+func (ttsp TgTypeSpecProperty) GetConstraints() *ValueConstraints {
+	return ttsp.constraints
+}
+
+func (ttsp *TgTypeSpecProperty) SetOrder(order int) {
+	ttsp.order = order
+}
+
+// GetOrder returns the position of the property in the official doc. Zero means the position is unknown.
+func (ttsp TgTypeSpecProperty) GetOrder() int {
+	return ttsp.order
+}
+
+func (ttsp *TgTypeSpecProperty) SetHistory(history *VersionHistory) {
+	ttsp.history = history
+}
+
+// GetHistory returns nil if versions of the property are unknown.
+func (ttsp TgTypeSpecProperty) GetHistory() *VersionHistory {
+	return ttsp.history
+}
+
+func (ttsp *TgTypeSpecProperty) SetProvenance(provenance *Provenance) {
+	ttsp.provenance = provenance
+}
+
+// GetProvenance returns nil if the property isn't scraped.
+func (ttsp TgTypeSpecProperty) GetProvenance() *Provenance {
+	return ttsp.provenance
+}
+
 type TgTypeSpecPropertyValue string
 
 func NewTgTypeSpec(category, name, link string) (*TgTypeSpec, error) {
@@ -152,6 +306,7 @@ func NewTgTypeSpec(category, name, link string) (*TgTypeSpec, error) {
 		category: category,
 		name:     name,
 		link:     link,
+		pa_mu:    &sync.RWMutex{},
 		c_mu:     &sync.RWMutex{},
 		p_mu:     &sync.RWMutex{},
 	}, nil
@@ -165,3 +320,23 @@ func NewTgTypeSpecProperty(name string) (*TgTypeSpecProperty, error) {
 
 	return &TgTypeSpecProperty{name: name, dt_mu: &sync.RWMutex{}}, nil
 }
+
+func appendTypeOnce(types []*TgTypeSpec, t *TgTypeSpec) []*TgTypeSpec {
+	for _, existing := range types {
+		if existing.name == t.name {
+			return types
+		}
+	}
+
+	return append(types, t)
+}
+
+func removeType(types []*TgTypeSpec, t *TgTypeSpec) []*TgTypeSpec {
+	for i, existing := range types {
+		if existing.name == t.name {
+			return append(types[:i:i], types[i+1:]...)
+		}
+	}
+
+	return types
+}
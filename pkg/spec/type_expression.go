@@ -0,0 +1,192 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TypeExpression is a node of the parsed data type definition. Definitions follow the grammar:
+//
+//	expression := term ( "|" term )*
+//	term       := "array" [ "<" expression ">" ] | identifier
+//	identifier := [A-Za-z_][A-Za-z0-9_]*
+//
+// Identifiers listed as scalar types (string, int32, etc.) are parsed as scalars, others as references to Telegram types.
+type TypeExpression interface {
+	// String returns the canonical form of the expression: without spaces, with deduplicated and sorted union members.
+	String() string
+}
+
+type ScalarTypeExpression struct {
+	Name string
+}
+
+func (e ScalarTypeExpression) String() string {
+	return e.Name
+}
+
+type ObjectTypeExpression struct {
+	Name string
+}
+
+func (e ObjectTypeExpression) String() string {
+	return e.Name
+}
+
+// ArrayTypeExpression describes an array. Element is nil if the data type of array elements is unknown.
+type ArrayTypeExpression struct {
+	Element TypeExpression
+}
+
+func (e ArrayTypeExpression) String() string {
+	if e.Element == nil {
+		return "array"
+	}
+
+	return "array<" + e.Element.String() + ">"
+}
+
+type UnionTypeExpression struct {
+	Members []TypeExpression
+}
+
+func (e UnionTypeExpression) String() string {
+	members := make([]string, len(e.Members))
+	for i, m := range e.Members {
+		members[i] = m.String()
+	}
+
+	return strings.Join(members, "|")
+}
+
+// ParseTypeExpression parses the data type definition, e.g. `array<array<InputMediaPhoto|InputMediaVideo>>`.
+// Union members are deduplicated and sorted, so equal unions have equal canonical forms.
+func ParseTypeExpression(definition string) (TypeExpression, error) {
+	p := &typeExpressionParser{input: definition}
+
+	expr, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, p.error("unexpected character")
+	}
+
+	return expr, nil
+}
+
+type typeExpressionParser struct {
+	input string
+	pos   int
+}
+
+func (p *typeExpressionParser) parseExpression() (TypeExpression, error) {
+	var members []TypeExpression
+	for {
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, term)
+
+		p.skipSpaces()
+		if p.pos >= len(p.input) || p.input[p.pos] != '|' {
+			break
+		}
+		p.pos++
+	}
+
+	return newUnionTypeExpression(members), nil
+}
+
+func (p *typeExpressionParser) parseTerm() (TypeExpression, error) {
+	p.skipSpaces()
+	identifier := p.parseIdentifier()
+	if identifier == "" {
+		return nil, p.error("identifier expected")
+	}
+
+	switch {
+	case identifier == "array":
+		p.skipSpaces()
+		if p.pos >= len(p.input) || p.input[p.pos] != '<' {
+			return &ArrayTypeExpression{}, nil
+		}
+		p.pos++
+
+		element, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpaces()
+		if p.pos >= len(p.input) || p.input[p.pos] != '>' {
+			return nil, p.error("'>' expected")
+		}
+		p.pos++
+
+		return &ArrayTypeExpression{Element: element}, nil
+	case isScalar(identifier):
+		return &ScalarTypeExpression{Name: identifier}, nil
+	}
+
+	return &ObjectTypeExpression{Name: identifier}, nil
+}
+
+func (p *typeExpressionParser) parseIdentifier() string {
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		isLetter := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+		isDigit := c >= '0' && c <= '9'
+		if !isLetter && !(isDigit && p.pos > start) {
+			break
+		}
+		p.pos++
+	}
+
+	return p.input[start:p.pos]
+}
+
+func (p *typeExpressionParser) skipSpaces() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *typeExpressionParser) error(msg string) error {
+	return errors.New(fmt.Sprintf("invalid data type definition '%s': %s at position %d", p.input, msg, p.pos))
+}
+
+// newUnionTypeExpression deduplicates and sorts members. A single member is returned as it is.
+// The grammar has no parentheses, so members are never unions themselves.
+func newUnionTypeExpression(members []TypeExpression) TypeExpression {
+	unique := make(map[string]TypeExpression)
+	for _, m := range members {
+		unique[m.String()] = m
+	}
+
+	if len(unique) == 1 {
+		for _, m := range unique {
+			return m
+		}
+	}
+
+	keys := make([]string, 0, len(unique))
+	for k := range unique {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	union := &UnionTypeExpression{Members: make([]TypeExpression, len(keys))}
+	for i, k := range keys {
+		union.Members[i] = unique[k]
+	}
+
+	return union
+}
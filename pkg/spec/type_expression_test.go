@@ -0,0 +1,114 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTypeExpression(t *testing.T) {
+	tests := []struct {
+		definition string
+		want       TypeExpression
+		canonical  string
+	}{
+		{
+			definition: "array<array<PhotoSize>>",
+			want:       &ArrayTypeExpression{Element: &ArrayTypeExpression{Element: &ObjectTypeExpression{Name: "PhotoSize"}}},
+			canonical:  "array<array<PhotoSize>>",
+		},
+		{
+			definition: "array<array<A|B>>",
+			want: &ArrayTypeExpression{Element: &ArrayTypeExpression{Element: &UnionTypeExpression{Members: []TypeExpression{
+				&ObjectTypeExpression{Name: "A"},
+				&ObjectTypeExpression{Name: "B"},
+			}}}},
+			canonical: "array<array<A|B>>",
+		},
+		{
+			definition: "B|A",
+			want:       &UnionTypeExpression{Members: []TypeExpression{&ObjectTypeExpression{Name: "A"}, &ObjectTypeExpression{Name: "B"}}},
+			canonical:  "A|B",
+		},
+		{
+			definition: "string|int64|string",
+			want:       &UnionTypeExpression{Members: []TypeExpression{&ScalarTypeExpression{Name: "int64"}, &ScalarTypeExpression{Name: "string"}}},
+			canonical:  "int64|string",
+		},
+		{
+			definition: "Message|Message",
+			want:       &ObjectTypeExpression{Name: "Message"},
+			canonical:  "Message",
+		},
+		{
+			definition: " array < string | boolean > ",
+			want:       &ArrayTypeExpression{Element: &UnionTypeExpression{Members: []TypeExpression{&ScalarTypeExpression{Name: "boolean"}, &ScalarTypeExpression{Name: "string"}}}},
+			canonical:  "array<boolean|string>",
+		},
+		{
+			definition: "array",
+			want:       &ArrayTypeExpression{},
+			canonical:  "array",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.definition, func(t *testing.T) {
+			expr, err := ParseTypeExpression(tt.definition)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(expr, tt.want) {
+				t.Errorf("got %#v, want %#v", expr, tt.want)
+			}
+			if expr.String() != tt.canonical {
+				t.Errorf("got canonical form %s, want %s", expr.String(), tt.canonical)
+			}
+		})
+	}
+}
+
+func TestParseMalformedTypeExpression(t *testing.T) {
+	for _, definition := range []string{"", "array<", "array<>", "A||B", "|A", "A|", "array<A", "array<A>>", "A B", "1A", "array<A,B>"} {
+		t.Run(definition, func(t *testing.T) {
+			if expr, err := ParseTypeExpression(definition); err == nil {
+				t.Errorf("expected the error, got %s", expr)
+			}
+		})
+	}
+}
+
+// FuzzParseTypeExpression checks that the parser never panics and that the canonical form is parsed into the same AST.
+func FuzzParseTypeExpression(f *testing.F) {
+	for _, seed := range []string{
+		"array<array<A|B>>",
+		"array<array<InlineKeyboardButton>>",
+		"array<InputMediaAudio|InputMediaDocument|InputMediaPhoto|InputMediaVideo>",
+		"array<array<int32|string>|Message>",
+		"int64|string",
+		"array",
+		"array < string | boolean >",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, definition string) {
+		expr, err := ParseTypeExpression(definition)
+		if err != nil {
+			return
+		}
+
+		canonical := expr.String()
+		reparsed, err := ParseTypeExpression(canonical)
+		if err != nil {
+			t.Fatalf("can't parse canonical form '%s' of '%s': %s", canonical, definition, err)
+		}
+
+		if !reflect.DeepEqual(expr, reparsed) {
+			t.Fatalf("canonical form '%s' of '%s' is parsed into another expression", canonical, definition)
+		}
+
+		if reparsed.String() != canonical {
+			t.Fatalf("canonical form '%s' isn't stable: '%s'", canonical, reparsed.String())
+		}
+	})
+}
@@ -12,6 +12,10 @@ type CompositeError struct {
 	problems []error
 }
 
+func NewCompositeError(problems []error) *CompositeError {
+	return &CompositeError{problems}
+}
+
 func (e *CompositeError) Error() string {
 	msg := fmt.Sprintf("%d problems detected:", len(e.problems))
 	for _, err := range e.problems {
@@ -21,17 +25,24 @@ func (e *CompositeError) Error() string {
 	return msg
 }
 
-func check(as ApiSpec) error {
-	var errs []error
+// Problems returns the list of detected problems. Problems found by SelfCheck are of the Diagnostic type.
+func (e *CompositeError) Problems() []error {
+	return e.problems
+}
 
-	checks := []func(as ApiSpec, ch chan<- error){
+func check(as ApiSpec) Diagnostics {
+	var diagnostics Diagnostics
+
+	checks := []func(as ApiSpec, ch chan<- Diagnostic){
 		checkMeta,
 		checkDataTypes,
 		checkTgTypes,
 		checkTgMethods,
+		checkAliases,
+		checkDescriptions,
 	}
 
-	ch := make(chan error)
+	ch := make(chan Diagnostic)
 	wg := &sync.WaitGroup{}
 
 	wg.Add(len(checks))
@@ -48,74 +59,208 @@ func check(as ApiSpec) error {
 		close(ch)
 	}()
 
-	for err := range ch {
-		errs = append(errs, err)
+	for d := range ch {
+		diagnostics = append(diagnostics, d)
 	}
 
-	if len(errs) != 0 {
-		return &CompositeError{errs}
-	}
+	diagnostics.sort()
 
-	return nil
+	return diagnostics
 }
 
-func checkMeta(as ApiSpec, ch chan<- error) {
+func checkMeta(as ApiSpec, ch chan<- Diagnostic) {
 	if as.GetVersion() == "" {
-		ch <- errors.New("version not set")
+		ch <- newError(CodeMissingVersion, "version", "version not set")
 	}
 
 	if as.GetReleaseDate() == "" {
-		ch <- errors.New("release date not set")
+		ch <- newError(CodeMissingReleaseDate, "releaseDate", "release date not set")
 	}
 
 	if as.GetLink() == "" {
-		ch <- errors.New("link not set")
+		ch <- newError(CodeMissingLink, "link", "link not set")
 	}
 }
 
-func checkDataTypes(as ApiSpec, ch chan<- error) {
+func checkDataTypes(as ApiSpec, ch chan<- Diagnostic) {
 	for _, dt := range as.GetDataTypeDefinitions() {
 		switch obj := dt.(type) {
 		case *ObjectDataType:
 			if obj.GetRef() == nil {
-				ch <- errors.New("incorrect object data type, reference missed: " + obj.GetDefinition())
+				ch <- newError(CodeUnresolvedReference, "dataTypes."+obj.GetDefinition(), "incorrect object data type, reference missed: %s", obj.GetDefinition())
+			}
+		}
+	}
+}
+
+// usedDataTypes returns the data types of properties, arguments, return types and aliases with their array elements and union members.
+func usedDataTypes(as ApiSpec) map[string]DataTypeDefinition {
+	used := make(map[string]DataTypeDefinition)
+
+	var mark func(dataTypes []DataTypeDefinition)
+	mark = func(dataTypes []DataTypeDefinition) {
+		for _, dt := range dataTypes {
+			if dt == nil {
+				continue
 			}
+			if _, visited := used[dt.GetDefinition()]; visited {
+				continue
+			}
+			used[dt.GetDefinition()] = dt
+
+			switch nested := dt.(type) {
+			case *ArrayDataType:
+				mark([]DataTypeDefinition{nested.GetElementDataType()})
+			case *UnionDataType:
+				mark(nested.GetMemberDataTypes())
+			}
+		}
+	}
+
+	for _, t := range as.GetTypes() {
+		for _, p := range t.GetProperties() {
+			mark(p.GetDataTypes())
 		}
 	}
+
+	for _, m := range as.GetMethods() {
+		mark(m.GetReturnTypes())
+		for _, cr := range m.GetConditionalReturns() {
+			mark([]DataTypeDefinition{cr.GetDataType()})
+		}
+		for _, a := range m.GetArguments() {
+			mark(a.GetDataTypes())
+		}
+	}
+
+	for _, alias := range as.GetAliases() {
+		mark([]DataTypeDefinition{alias.GetDataType()})
+	}
+
+	return used
 }
 
-func checkTgTypes(as ApiSpec, ch chan<- error) {
+func checkTgTypes(as ApiSpec, ch chan<- Diagnostic) {
 	for _, t := range as.GetTypes() {
 		for _, p := range t.GetProperties() {
 			if len(p.GetDataTypes()) == 0 {
-				ch <- errors.New(fmt.Sprintf("incorrect property, data type missed (object: %s property: %s", t.GetName(), p.GetName()))
+				ch <- newError(CodeMissingDataType, propertyPath(t, p), "incorrect property, data type missed (object: %s, property: %s)", t.GetName(), p.GetName())
 			}
 		}
 
-		parent := t.GetParent()
-		if parent != nil {
+		for _, parent := range t.GetParents() {
 			if _, exists := as.GetType(parent.GetName()); !exists {
-				ch <- errors.New(fmt.Sprintf("object %s has a parent %s which is missing in the objects list", t.GetName(), parent.GetName()))
+				ch <- newError(CodeMissingParent, typePath(t), "object %s has a parent %s which is missing in the objects list", t.GetName(), parent.GetName())
+			}
+
+			if !containsType(parent.GetChildren(), t) {
+				ch <- newError(CodeAsymmetricRelation, typePath(t), "object %s has a parent %s which doesn't list it as a child", t.GetName(), parent.GetName())
 			}
 		}
 
 		for _, c := range t.GetChildren() {
 			if _, exists := as.GetType(c.GetName()); !exists {
-				ch <- errors.New(fmt.Sprintf("object %s has a child %s which is missing in the objects list", t.GetName(), c.GetName()))
+				ch <- newError(CodeMissingChild, typePath(t), "object %s has a child %s which is missing in the objects list", t.GetName(), c.GetName())
 			}
+
+			if !containsType(c.GetParents(), t) {
+				ch <- newError(CodeAsymmetricRelation, typePath(t), "object %s has a child %s which doesn't list it as a parent", t.GetName(), c.GetName())
+			}
+		}
+
+		discriminator, err := resolveDiscriminator(*t)
+		var duplicates duplicateValuesError
+		switch {
+		case errors.As(err, &duplicates):
+			ch <- newWarning(CodeDuplicateDiscriminatorValue, typePath(t), "%s", err.Error())
+		case err != nil:
+			ch <- newError(CodeInconsistentDiscriminator, typePath(t), "%s", err.Error())
+		case discriminator == nil && len(t.GetChildren()) > 0:
+			ch <- newInfo(CodeUndeterminedDiscriminator, typePath(t), "children of object %s can't be told apart by a value of one property", t.GetName())
 		}
 	}
 }
 
-func checkTgMethods(as ApiSpec, ch chan<- error) {
+func containsType(types []*TgTypeSpec, t *TgTypeSpec) bool {
+	for _, item := range types {
+		if item.GetName() == t.GetName() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func checkTgMethods(as ApiSpec, ch chan<- Diagnostic) {
 	for _, m := range as.GetMethods() {
 		if len(m.GetReturnTypes()) == 0 {
-			ch <- errors.New("return types not set, method: " + m.GetName())
+			ch <- newError(CodeMissingReturnType, methodPath(m), "return types not set, method: %s", m.GetName())
 		}
 
+		argNames := make(map[string]bool)
 		for _, a := range m.GetArguments() {
+			argNames[a.GetName()] = true
 			if len(a.GetDataTypes()) == 0 {
-				ch <- errors.New(fmt.Sprintf("incorrect argument, data type missed (method: %s argument: %s", m.GetName(), a.GetName()))
+				ch <- newError(CodeMissingDataType, argumentPath(m, a), "incorrect argument, data type missed (method: %s, argument: %s)", m.GetName(), a.GetName())
+			}
+		}
+
+		returns := make(map[string]bool)
+		for _, r := range FlattenDataTypes(m.GetReturnTypes()) {
+			returns[r.GetDefinition()] = true
+		}
+
+		for _, cr := range m.GetConditionalReturns() {
+			if !returns[cr.GetDataType().GetDefinition()] {
+				ch <- newError(CodeUnknownConditionalReturn, methodPath(m), "method %s has a conditional return type %s which is missing in the return types list", m.GetName(), cr.GetDataType().GetDefinition())
+			}
+
+			for _, name := range cr.GetArguments() {
+				if !argNames[name] {
+					ch <- newError(CodeUnknownArgument, methodPath(m), "method %s has a conditional return type %s which depends on missing argument %s", m.GetName(), cr.GetDataType().GetDefinition(), name)
+				}
+			}
+		}
+
+		for _, g := range m.GetArgumentGroups() {
+			for _, name := range append(append([]string{}, g.GetArguments()...), g.GetDependsOn()...) {
+				if !argNames[name] {
+					ch <- newError(CodeUnknownArgument, methodPath(m), "method %s has a group %s with argument %s which is missing in the arguments list", m.GetName(), g, name)
+				}
+			}
+		}
+	}
+}
+
+func checkAliases(as ApiSpec, ch chan<- Diagnostic) {
+	for _, alias := range as.GetAliases() {
+		if _, exists := as.GetType(alias.GetName()); exists {
+			ch <- newError(CodeAliasConflict, "aliases."+alias.GetName(), "alias %s conflicts with the object of the same name", alias.GetName())
+		}
+	}
+}
+
+func checkDescriptions(as ApiSpec, ch chan<- Diagnostic) {
+	for _, t := range as.GetTypes() {
+		if strings.TrimSpace(t.GetDescription()) == "" {
+			ch <- newWarning(CodeMissingDescription, typePath(t), "object %s has no description", t.GetName())
+		}
+
+		for _, p := range t.GetProperties() {
+			if strings.TrimSpace(p.GetDescription()) == "" {
+				ch <- newWarning(CodeMissingDescription, propertyPath(t, p), "property %s of object %s has no description", p.GetName(), t.GetName())
+			}
+		}
+	}
+
+	for _, m := range as.GetMethods() {
+		if strings.TrimSpace(m.GetDescription()) == "" {
+			ch <- newWarning(CodeMissingDescription, methodPath(m), "method %s has no description", m.GetName())
+		}
+
+		for _, a := range m.GetArguments() {
+			if strings.TrimSpace(a.GetDescription()) == "" {
+				ch <- newWarning(CodeMissingDescription, argumentPath(m, a), "argument %s of method %s has no description", a.GetName(), m.GetName())
 			}
 		}
 	}
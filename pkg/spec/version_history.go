@@ -0,0 +1,13 @@
+package spec
+
+// VersionHistory describes Bot API versions of the element: when it was introduced, changed for the last time and removed.
+// An empty field means the version is unknown or, for Until, that the element isn't removed.
+type VersionHistory struct {
+	Since       string
+	LastChanged string
+	Until       string
+}
+
+func (vh VersionHistory) IsEmpty() bool {
+	return vh.Since == "" && vh.LastChanged == "" && vh.Until == ""
+}